@@ -0,0 +1,523 @@
+package gomonnify
+
+import (
+	"errors"
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// IntentPending is the status of an intent waiting for approvals.
+	IntentPending IntentStatus = "PENDING"
+	// IntentApproved is the status of an intent that has all its approvals and is being sent to Monnify. An intent
+	// left APPROVED was interrupted while being sent; check whether Monnify has the transfer before proposing it again.
+	IntentApproved IntentStatus = "APPROVED"
+	// IntentSubmitted is the status of an intent whose transfer Monnify accepted.
+	IntentSubmitted IntentStatus = "SUBMITTED"
+	// IntentFailed is the status of an approved intent whose transfer could not be sent, see TransferIntent.Error.
+	IntentFailed IntentStatus = "FAILED"
+	// IntentRejected is the status of an intent turned down by an approver.
+	IntentRejected IntentStatus = "REJECTED"
+	// IntentExpired is the status of an intent that did not get its approvals in time.
+	IntentExpired IntentStatus = "EXPIRED"
+
+	AuditProposed  AuditAction = "PROPOSED"
+	AuditApproved  AuditAction = "APPROVED"
+	AuditRejected  AuditAction = "REJECTED"
+	AuditDenied    AuditAction = "DENIED"
+	AuditExpired   AuditAction = "EXPIRED"
+	AuditSubmitted AuditAction = "SUBMITTED"
+	AuditFailed    AuditAction = "FAILED"
+
+	// DefaultIntentTTL is how long an intent waits for its approvals when ApprovalGate.TTL is not set.
+	DefaultIntentTTL = 24 * time.Hour
+)
+
+var (
+	// ErrIntentExists is returned when a transfer is proposed with the reference of an existing intent.
+	ErrIntentExists = errors.New("an intent already exists for this reference")
+	// ErrIntentNotFound is returned when no intent exists for the id.
+	ErrIntentNotFound = errors.New("intent not found")
+	// ErrIntentNotPending is returned when an intent that was already decided is approved or rejected.
+	ErrIntentNotPending = errors.New("intent is not pending")
+	// ErrIntentExpired is returned when an intent is approved or rejected after it expired.
+	ErrIntentExpired = errors.New("intent has expired")
+	// ErrIntentConflict is returned by IntentStore.Update when the intent was changed since it was read.
+	ErrIntentConflict = errors.New("intent was changed concurrently")
+	// ErrSelfApproval is returned when the maker of an intent tries to approve it.
+	ErrSelfApproval = errors.New("an intent cannot be approved by its maker")
+	// ErrDuplicateApproval is returned when an approver approves the same intent twice.
+	ErrDuplicateApproval = errors.New("intent was already approved by this approver")
+)
+
+type (
+	// IntentStatus is the status of a TransferIntent.
+	IntentStatus string
+
+	// AuditAction is the decision recorded by an AuditEvent.
+	AuditAction string
+
+	// Approval is the approval of an intent by an approver.
+	Approval struct {
+		ApproverID string    `json:"approverId"`
+		At         time.Time `json:"at"`
+	}
+
+	// TransferIntent is a single or bulk transfer waiting for approval. Exactly one of Single and Bulk is set, and ID is
+	// the reference of the transfer or the batch. Version is incremented on every change, see IntentStore.Update.
+	TransferIntent struct {
+		ID                string                      `json:"id"`
+		MakerID           string                      `json:"makerId"`
+		Single            *params.SingleTransferParam `json:"single,omitempty"`
+		Bulk              *params.BulkTransferParam   `json:"bulk,omitempty"`
+		Status            IntentStatus                `json:"status"`
+		RequiredApprovals int                         `json:"requiredApprovals"`
+		Approvals         []Approval                  `json:"approvals"`
+		CreatedAt         time.Time                   `json:"createdAt"`
+		ExpiresAt         time.Time                   `json:"expiresAt"`
+		UpdatedAt         time.Time                   `json:"updatedAt"`
+		Error             string                      `json:"error,omitempty"`
+		Version           int                         `json:"version"`
+	}
+
+	// IntentStore keeps transfer intents. Implementations must be safe for concurrent use, and should be persistent
+	// so that pending intents survive a restart.
+	IntentStore interface {
+		// Create records a new intent. It returns false, without changing anything, if the id is already recorded.
+		Create(intent TransferIntent) (bool, error)
+		// Get returns the intent and whether it exists.
+		Get(id string) (TransferIntent, bool, error)
+		// Update replaces an existing intent. It must return ErrIntentConflict, without changing anything, unless the
+		// recorded intent is at intent.Version - 1.
+		Update(intent TransferIntent) error
+		// List returns the intents in status.
+		List(status IntentStatus) ([]TransferIntent, error)
+	}
+
+	// AuditEvent records a decision taken on an intent. ActorID is empty for decisions taken by the gate itself, i.e.
+	// expiry and submission. Status is the status of the intent after the decision; denied attempts leave it unchanged
+	// and give the Reason they were denied.
+	AuditEvent struct {
+		IntentID string
+		Action   AuditAction
+		ActorID  string
+		Status   IntentStatus
+		Reason   string
+		At       time.Time
+	}
+
+	// AuditSink records the decisions of an ApprovalGate, e.g. in an append-only table or a log pipeline.
+	AuditSink interface {
+		Record(event AuditEvent) error
+	}
+
+	// AuditSinkFunc adapts a function to an AuditSink.
+	AuditSinkFunc func(event AuditEvent) error
+
+	// ApprovalGate holds single and bulk transfers until they are approved, so that no single credential can move money
+	// on its own. A maker proposes a transfer, which is stored as a pending intent. It is sent to Monnify once
+	// RequiredApprovals approvers, all distinct and other than the maker, approved it. One rejection turns it down, and
+	// intents that are not approved within TTL expire.
+	//
+	// Every decision, including denied attempts, is recorded in Audit. Approvals, rejections and expiries are recorded
+	// before they take effect, and are not taken if that fails. If the intent then cannot be saved, e.g. because a
+	// concurrent decision was saved first, the decision is followed by a DENIED event giving the reason.
+	// Clock returns the current time and defaults to time.Now.
+	ApprovalGate struct {
+		Disbursements     DisbursementsAPI
+		Store             IntentStore
+		Audit             AuditSink
+		RequiredApprovals int
+		TTL               time.Duration
+		Clock             func() time.Time
+	}
+
+	// ApprovalResult is the outcome of an approval. The transfer response is set once the intent was submitted.
+	ApprovalResult struct {
+		Intent         TransferIntent
+		SingleTransfer *SingleTransferResponse
+		BulkTransfer   *BulkTransferResponse
+	}
+
+	// MemoryIntentStore is an IntentStore that keeps the intents in memory. It does not survive a restart, and is meant
+	// for tests and single process setups.
+	MemoryIntentStore struct {
+		mu      sync.Mutex
+		intents map[string]TransferIntent
+	}
+)
+
+func (f AuditSinkFunc) Record(event AuditEvent) error {
+	return f(event)
+}
+
+// NewApprovalGate returns an ApprovalGate sending approved transfers through d once they have requiredApprovals.
+func NewApprovalGate(d DisbursementsAPI, store IntentStore, audit AuditSink, requiredApprovals int) *ApprovalGate {
+	return &ApprovalGate{Disbursements: d, Store: store, Audit: audit, RequiredApprovals: requiredApprovals, TTL: DefaultIntentTTL}
+}
+
+// ProposeSingleTransfer stores the transfer as a pending intent, identified by its reference.
+func (g *ApprovalGate) ProposeSingleTransfer(makerID string, param params.SingleTransferParam) (*TransferIntent, error) {
+	if err := param.Validate(); err != nil {
+		return nil, err
+	}
+	return g.propose(TransferIntent{ID: param.Reference, MakerID: makerID, Single: &param})
+}
+
+// ProposeBulkTransfer stores the bulk transfer as a pending intent, identified by its batch reference.
+func (g *ApprovalGate) ProposeBulkTransfer(makerID string, param params.BulkTransferParam) (*TransferIntent, error) {
+	if err := param.Validate(); err != nil {
+		return nil, err
+	}
+	return g.propose(TransferIntent{ID: param.BatchReference, MakerID: makerID, Bulk: &param})
+}
+
+func (g *ApprovalGate) propose(intent TransferIntent) (*TransferIntent, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	if intent.MakerID == "" {
+		return nil, errors.New("makerID is required")
+	}
+
+	ttl := g.TTL
+	if ttl <= 0 {
+		ttl = DefaultIntentTTL
+	}
+	now := g.now()
+	intent.Status = IntentPending
+	intent.RequiredApprovals = g.RequiredApprovals
+	intent.CreatedAt = now
+	intent.ExpiresAt = now.Add(ttl)
+	intent.UpdatedAt = now
+	intent.Version = 1
+
+	// The store and the caller get their own copies, so the proposed transfer cannot be changed through the params
+	// or the returned intent.
+	created, err := g.Store.Create(intent.clone())
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrIntentExists
+	}
+	if err := g.record(intent, AuditProposed, intent.MakerID, ""); err != nil {
+		return nil, err
+	}
+	proposed := intent.clone()
+	return &proposed, nil
+}
+
+// Approve records the approval of the intent by approverID and, if it was the last one required, sends the transfer.
+// The approval is denied with ErrSelfApproval, ErrDuplicateApproval, ErrIntentNotPending or ErrIntentExpired.
+// Once sent, the intent is SUBMITTED or, if Monnify did not accept the transfer, FAILED with the error returned.
+func (g *ApprovalGate) Approve(id, approverID string) (*ApprovalResult, error) {
+	intent, err := g.pending(id, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case approverID == intent.MakerID:
+		return nil, g.deny(intent, approverID, ErrSelfApproval)
+	case intent.approvedBy(approverID):
+		return nil, g.deny(intent, approverID, ErrDuplicateApproval)
+	}
+
+	now := g.now()
+	intent.Approvals = append(intent.Approvals, Approval{ApproverID: approverID, At: now})
+	if len(intent.Approvals) >= intent.RequiredApprovals {
+		intent.Status = IntentApproved
+	}
+	if err := g.update(&intent, AuditApproved, approverID, ""); err != nil {
+		return nil, err
+	}
+
+	result := &ApprovalResult{Intent: intent}
+	if intent.Status != IntentApproved {
+		return result, nil
+	}
+
+	var submitErr error
+	if intent.Single != nil {
+		result.SingleTransfer, submitErr = g.Disbursements.SingleTransfer(*intent.Single)
+	} else {
+		result.BulkTransfer, submitErr = g.Disbursements.BulkTransfer(*intent.Bulk)
+	}
+
+	action, reason := AuditSubmitted, ""
+	intent.Status = IntentSubmitted
+	if submitErr != nil {
+		action, reason = AuditFailed, submitErr.Error()
+		intent.Status = IntentFailed
+		intent.Error = reason
+	}
+	intent.Version++
+	intent.UpdatedAt = g.now()
+	result.Intent = intent
+	if err := g.Store.Update(intent); err != nil {
+		return result, err
+	}
+	if err := g.record(intent, action, "", reason); err != nil {
+		return result, err
+	}
+	return result, submitErr
+}
+
+// Reject turns the intent down, so it is never sent. It can be rejected by any approver, or withdrawn by its maker.
+func (g *ApprovalGate) Reject(id, approverID, reason string) (*TransferIntent, error) {
+	intent, err := g.pending(id, approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	intent.Status = IntentRejected
+	if err := g.update(&intent, AuditRejected, approverID, reason); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// Intent returns the intent, expiring it first if it is past its expiry.
+func (g *ApprovalGate) Intent(id string) (*TransferIntent, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	intent, found, err := g.Store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrIntentNotFound
+	}
+	if _, err := g.expire(&intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// Pending returns the intents waiting for approvals, oldest first. Intents past their expiry are expired instead.
+func (g *ApprovalGate) Pending() ([]TransferIntent, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	intents, err := g.Store.List(IntentPending)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []TransferIntent
+	for i := range intents {
+		if _, err := g.expire(&intents[i]); err != nil {
+			return nil, err
+		}
+		if intents[i].Status == IntentPending {
+			pending = append(pending, intents[i])
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].CreatedAt.Before(pending[j].CreatedAt) })
+	return pending, nil
+}
+
+// pending returns the intent if a decision can be taken on it, denying the attempt otherwise.
+func (g *ApprovalGate) pending(id, actorID string) (TransferIntent, error) {
+	if err := g.validate(); err != nil {
+		return TransferIntent{}, err
+	}
+	if actorID == "" {
+		return TransferIntent{}, errors.New("approverID is required")
+	}
+
+	intent, found, err := g.Store.Get(id)
+	if err != nil {
+		return TransferIntent{}, err
+	}
+	if !found {
+		return TransferIntent{}, ErrIntentNotFound
+	}
+
+	expired, err := g.expire(&intent)
+	if err != nil {
+		return TransferIntent{}, err
+	}
+	switch {
+	case expired || intent.Status == IntentExpired:
+		return TransferIntent{}, g.deny(intent, actorID, ErrIntentExpired)
+	case intent.Status != IntentPending:
+		return TransferIntent{}, g.deny(intent, actorID, ErrIntentNotPending)
+	}
+	return intent, nil
+}
+
+// expire moves a pending intent past its expiry to EXPIRED and reports whether it did. If the intent was changed
+// concurrently, it is reloaded instead.
+func (g *ApprovalGate) expire(intent *TransferIntent) (bool, error) {
+	if intent.Status != IntentPending || g.now().Before(intent.ExpiresAt) {
+		return false, nil
+	}
+	previous := *intent
+	intent.Status = IntentExpired
+	err := g.update(intent, AuditExpired, "", "")
+	if errors.Is(err, ErrIntentConflict) {
+		current, found, getErr := g.Store.Get(intent.ID)
+		if getErr != nil {
+			return false, getErr
+		}
+		if !found {
+			return false, ErrIntentNotFound
+		}
+		*intent = current
+		return false, nil
+	}
+	if err != nil {
+		*intent = previous
+		return false, err
+	}
+	return true, nil
+}
+
+// update records the decision in the audit sink, then saves the intent. If it cannot be saved, a DENIED event is
+// recorded so the audit trail does not show a decision that never took effect.
+func (g *ApprovalGate) update(intent *TransferIntent, action AuditAction, actorID, reason string) error {
+	intent.Version++
+	intent.UpdatedAt = g.now()
+	if err := g.record(*intent, action, actorID, reason); err != nil {
+		return err
+	}
+	err := g.Store.Update(*intent)
+	if err == nil {
+		return nil
+	}
+
+	// Decisions are only taken on pending intents, so that is the status left when the intent cannot be reloaded.
+	denied := *intent
+	denied.Status = IntentPending
+	if current, found, getErr := g.Store.Get(intent.ID); getErr == nil && found {
+		denied = current
+	}
+	return g.deny(denied, actorID, err)
+}
+
+// deny records an attempt that was refused with err, and returns err.
+func (g *ApprovalGate) deny(intent TransferIntent, actorID string, err error) error {
+	if auditErr := g.record(intent, AuditDenied, actorID, err.Error()); auditErr != nil {
+		return auditErr
+	}
+	return err
+}
+
+func (g *ApprovalGate) record(intent TransferIntent, action AuditAction, actorID, reason string) error {
+	err := g.Audit.Record(AuditEvent{
+		IntentID: intent.ID,
+		Action:   action,
+		ActorID:  actorID,
+		Status:   intent.Status,
+		Reason:   reason,
+		At:       g.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to record %v decision on intent %v: %w", action, intent.ID, err)
+	}
+	return nil
+}
+
+func (g *ApprovalGate) validate() error {
+	switch {
+	case g.Disbursements == nil:
+		return errors.New("Disbursements is required")
+	case g.Store == nil:
+		return errors.New("Store is required")
+	case g.Audit == nil:
+		return errors.New("Audit is required")
+	case g.RequiredApprovals <= 0:
+		return errors.New("RequiredApprovals must be at least 1")
+	}
+	return nil
+}
+
+func (g *ApprovalGate) now() time.Time {
+	if g.Clock != nil {
+		return g.Clock().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (i TransferIntent) approvedBy(approverID string) bool {
+	for _, a := range i.Approvals {
+		if a.ApproverID == approverID {
+			return true
+		}
+	}
+	return false
+}
+
+// NewMemoryIntentStore returns an empty MemoryIntentStore.
+func NewMemoryIntentStore() *MemoryIntentStore {
+	return &MemoryIntentStore{intents: map[string]TransferIntent{}}
+}
+
+func (s *MemoryIntentStore) Create(intent TransferIntent) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.intents[intent.ID]; ok {
+		return false, nil
+	}
+	s.intents[intent.ID] = intent.clone()
+	return true, nil
+}
+
+func (s *MemoryIntentStore) Get(id string) (TransferIntent, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	intent, ok := s.intents[id]
+	return intent.clone(), ok, nil
+}
+
+func (s *MemoryIntentStore) Update(intent TransferIntent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.intents[intent.ID]
+	if !ok {
+		return fmt.Errorf("intent %v is not recorded", intent.ID)
+	}
+	if existing.Version != intent.Version-1 {
+		return ErrIntentConflict
+	}
+	s.intents[intent.ID] = intent.clone()
+	return nil
+}
+
+func (s *MemoryIntentStore) List(status IntentStatus) ([]TransferIntent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var intents []TransferIntent
+	for _, intent := range s.intents {
+		if intent.Status == status {
+			intents = append(intents, intent.clone())
+		}
+	}
+	return intents, nil
+}
+
+// clone copies the approvals, so intents handed out by MemoryIntentStore do not share them with the stored ones.
+// clone returns a deep copy of the intent, so that the transfer it holds cannot be changed through another copy.
+func (i TransferIntent) clone() TransferIntent {
+	i.Approvals = append([]Approval(nil), i.Approvals...)
+	if i.Single != nil {
+		single := *i.Single
+		i.Single = &single
+	}
+	if i.Bulk != nil {
+		bulk := *i.Bulk
+		bulk.TransactionList = append([]params.SingleTransferParam(nil), bulk.TransactionList...)
+		i.Bulk = &bulk
+	}
+	return i
+}
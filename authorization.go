@@ -0,0 +1,309 @@
+package gomonnify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrOTPAttemptsExhausted is returned by TransferWithAuthorization and BulkTransferWithAuthorization when
+// AuthorizationPolicy.MaxAttempts OTPs were rejected. The transfer is left pending authorization.
+var ErrOTPAttemptsExhausted = errors.New("transfer was not authorized within the allowed OTP attempts")
+
+// ErrOTPChannelClosed is returned by an OTPChannel that is closed before an OTP is received.
+var ErrOTPChannelClosed = errors.New("otp channel closed")
+
+// ErrBatchOTPNotSent is returned by BulkTransferWithAuthorization when Monnify could not email the OTP of a batch.
+// ResendOTP only covers single transfers, so the batch is left pending authorization.
+var ErrBatchOTPNotSent = errors.New("otp of the batch could not be emailed and cannot be resent")
+
+// DefaultAuthorizationPolicy is used for every AuthorizationPolicy field left at its zero value.
+var DefaultAuthorizationPolicy = AuthorizationPolicy{
+	MaxAttempts:       3,
+	ResendInterval:    30 * time.Second,
+	MaxResendInterval: 5 * time.Minute,
+	Multiplier:        2,
+}
+
+type (
+	// AuthorizationPolicy controls how a transfer waiting for its OTP is authorized. Up to MaxAttempts OTPs are
+	// submitted. After a rejected OTP, a new one is requested with ResendOTP once ResendInterval has passed; every
+	// following interval is multiplied by Multiplier, up to MaxResendInterval.
+	AuthorizationPolicy struct {
+		MaxAttempts       int
+		ResendInterval    time.Duration
+		MaxResendInterval time.Duration
+		Multiplier        float64
+	}
+
+	// OTPRequest describes the OTP an OTPProvider is asked for. Attempt starts at 1 and Resent is true when a new OTP
+	// was requested with ResendOTP since the previous attempt.
+	OTPRequest struct {
+		Reference string `json:"reference"`
+		Batch     bool   `json:"batch"`
+		Attempt   int    `json:"attempt"`
+		Resent    bool   `json:"resent"`
+	}
+
+	// OTPProvider supplies the OTP Monnify sent for a transfer, e.g. by asking the user who received it.
+	// OTP should block until the OTP is available or ctx is done.
+	OTPProvider interface {
+		OTP(ctx context.Context, request OTPRequest) (string, error)
+	}
+
+	// OTPProviderFunc adapts a function to an OTPProvider.
+	OTPProviderFunc func(ctx context.Context, request OTPRequest) (string, error)
+
+	// OTPChannel is an OTPProvider that receives OTPs from a channel, one per request.
+	OTPChannel <-chan string
+
+	// OTPPrompt is an OTPProvider that waits for the OTPs to be submitted over HTTP. Mount it on a route of your
+	// server: a GET lists the pending OTPRequests as JSON and a POST of {"reference": "...", "otp": "..."} supplies
+	// the OTP of a pending request. Only one request per reference can be pending at a time.
+	OTPPrompt struct {
+		mu      sync.Mutex
+		pending map[string]*pendingOTP
+	}
+
+	pendingOTP struct {
+		request OTPRequest
+		otp     chan string
+	}
+)
+
+func (f OTPProviderFunc) OTP(ctx context.Context, request OTPRequest) (string, error) {
+	return f(ctx, request)
+}
+
+func (c OTPChannel) OTP(ctx context.Context, request OTPRequest) (string, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case otp, ok := <-c:
+		if !ok {
+			return "", ErrOTPChannelClosed
+		}
+		return otp, nil
+	}
+}
+
+// NewOTPPrompt returns an OTPPrompt without pending requests.
+func NewOTPPrompt() *OTPPrompt {
+	return &OTPPrompt{pending: map[string]*pendingOTP{}}
+}
+
+func (p *OTPPrompt) OTP(ctx context.Context, request OTPRequest) (string, error) {
+	pending := &pendingOTP{request: request, otp: make(chan string, 1)}
+
+	p.mu.Lock()
+	if _, ok := p.pending[request.Reference]; ok {
+		p.mu.Unlock()
+		return "", fmt.Errorf("an otp is already pending for reference %v", request.Reference)
+	}
+	p.pending[request.Reference] = pending
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, request.Reference)
+		p.mu.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case otp := <-pending.otp:
+		return otp, nil
+	}
+}
+
+// Pending returns the requests waiting for an OTP, ordered by reference.
+func (p *OTPPrompt) Pending() []OTPRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	requests := make([]OTPRequest, 0, len(p.pending))
+	for _, pending := range p.pending {
+		requests = append(requests, pending.request)
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Reference < requests[j].Reference })
+	return requests
+}
+
+// Submit supplies the OTP of the pending request for reference. It returns false if no request is pending for it or
+// an OTP was already submitted.
+func (p *OTPPrompt) Submit(reference, otp string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pending, ok := p.pending[reference]
+	if !ok {
+		return false
+	}
+	select {
+	case pending.otp <- otp:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *OTPPrompt) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(p.Pending())
+	case http.MethodPost:
+		var body struct {
+			Reference string `json:"reference"`
+			OTP       string `json:"otp"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Reference == "" || body.OTP == "" {
+			http.Error(w, "reference and otp are required", http.StatusBadRequest)
+			return
+		}
+		if !p.Submit(body.Reference, body.OTP) {
+			http.Error(w, fmt.Sprintf("no otp is pending for reference %v", body.Reference), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// TransferWithAuthorization sends the transfer and, if Monnify holds it for authorization, gets its OTP from provider
+// and authorizes it. OTPs are requested again with ResendOTP as described by policy, straight away if Monnify could
+// not email the first one.
+// The transfer is returned with its latest status. When it could not be authorized, e.g. the attempts are exhausted or
+// ctx is done, the transfer is returned with the error and is left pending authorization.
+func TransferWithAuthorization(ctx context.Context, d DisbursementsAPI, param params.SingleTransferParam, provider OTPProvider, policy AuthorizationPolicy) (*SingleTransferResponse, error) {
+	if provider == nil {
+		return nil, errors.New("provider is required")
+	}
+
+	result, err := d.SingleTransfer(param)
+	if err != nil {
+		return nil, err
+	}
+	status := result.ResponseBody.Status
+	if !status.RequiresAuthorization() {
+		return result, nil
+	}
+
+	request := OTPRequest{Reference: result.ResponseBody.Reference}
+	resend := status == TransferStatusOTPEmailDispatchFailed
+	err = authorize(ctx, d, request, resend, provider, policy, func(otp string) error {
+		authorized, err := d.AuthorizeSingleTransfer(request.Reference, otp)
+		if err == nil {
+			result = authorized
+		}
+		return err
+	})
+	return result, err
+}
+
+// BulkTransferWithAuthorization is TransferWithAuthorization for bulk transfers, authorized with AuthorizeBulkTransfer.
+// Monnify documents no way to resend the OTP of a batch, so after a rejected OTP the provider is asked again for the
+// one already sent, and ErrBatchOTPNotSent is returned if Monnify could not email it.
+func BulkTransferWithAuthorization(ctx context.Context, d DisbursementsAPI, param params.BulkTransferParam, provider OTPProvider, policy AuthorizationPolicy) (*BulkTransferResponse, error) {
+	if provider == nil {
+		return nil, errors.New("provider is required")
+	}
+
+	result, err := d.BulkTransfer(param)
+	if err != nil {
+		return nil, err
+	}
+	status := result.ResponseBody.BatchStatus
+	if !status.RequiresAuthorization() {
+		return result, nil
+	}
+
+	if status == BatchStatusOTPEmailDispatchFailed {
+		return result, ErrBatchOTPNotSent
+	}
+
+	request := OTPRequest{Reference: result.ResponseBody.BatchReference, Batch: true}
+	err = authorize(ctx, d, request, false, provider, policy, func(otp string) error {
+		authorized, err := d.AuthorizeBulkTransfer(request.Reference, otp)
+		if err == nil {
+			result = authorized
+		}
+		return err
+	})
+	return result, err
+}
+
+// authorize submits OTPs from provider until one is accepted. OTPs Monnify rejected, with a 4xx or an unsuccessful
+// HTTP 200 response, are followed by a resend after the backoff interval, except for batches which are asked again
+// without one; any other error is returned as is.
+func authorize(ctx context.Context, d DisbursementsAPI, request OTPRequest, resend bool, provider OTPProvider, policy AuthorizationPolicy, submit func(otp string) error) error {
+	policy = policy.withDefaults()
+	interval := policy.ResendInterval
+
+	for attempt := 1; ; attempt++ {
+		if resend {
+			if _, err := d.ResendOTP(request.Reference); err != nil {
+				return err
+			}
+		}
+		request.Attempt = attempt
+		request.Resent = resend
+
+		otp, err := provider.OTP(ctx, request)
+		if err != nil {
+			return err
+		}
+		err = submit(otp)
+		if err == nil {
+			return nil
+		}
+		if !isRejected(err) {
+			return err
+		}
+		if attempt >= policy.MaxAttempts {
+			return fmt.Errorf("%w: %v", ErrOTPAttemptsExhausted, err)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		resend = !request.Batch
+
+		interval = time.Duration(float64(interval) * policy.Multiplier)
+		if interval > policy.MaxResendInterval {
+			interval = policy.MaxResendInterval
+		}
+	}
+}
+
+func (p AuthorizationPolicy) withDefaults() AuthorizationPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultAuthorizationPolicy.MaxAttempts
+	}
+	if p.ResendInterval <= 0 {
+		p.ResendInterval = DefaultAuthorizationPolicy.ResendInterval
+	}
+	if p.MaxResendInterval <= 0 {
+		p.MaxResendInterval = DefaultAuthorizationPolicy.MaxResendInterval
+	}
+	if p.MaxResendInterval < p.ResendInterval {
+		p.MaxResendInterval = p.ResendInterval
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultAuthorizationPolicy.Multiplier
+	}
+	return p
+}
@@ -2,14 +2,13 @@ package gomonnify
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"github.com/jcobhams/gomonnify/testhelpers"
 	"io"
-	"io/ioutil"
 	"net/http"
-	"os"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -17,14 +16,9 @@ import (
 //Base or shared methods
 
 func newBase(config Config) *base {
-
-	if tm, ok := os.LookupEnv("GOMONNIFY_TESTMODE"); ok && strings.ToUpper(tm) == "ON" {
-		config.Environment = EnvTest
-	}
-
 	b := &base{
 		Config:     &config,
-		HTTPClient: &http.Client{Timeout: config.RequestTimeout},
+		HTTPClient: &http.Client{Timeout: config.RequestTimeout, Transport: config.Transport},
 	}
 
 	switch config.Environment {
@@ -32,109 +26,130 @@ func newBase(config Config) *base {
 		b.APIBaseUrl = APIBaseUrlSandbox
 	case EnvLive:
 		b.APIBaseUrl = APIBaseUrlLive
-	case EnvTest:
-		if testUrl, ok := os.LookupEnv("GOMONNIFY_TESTURL"); ok {
-			b.APIBaseUrl = testUrl
-			b.Config.SecretKey = testhelpers.SecretKey
-		} else {
-			panic("gomonnify is running in test mode but no test url provided. Please Set GOMONNIFY_TESTURL env var")
-		}
+	}
+
+	if config.BaseURL != "" {
+		b.APIBaseUrl = strings.TrimSuffix(config.BaseURL, "/")
 	}
 
 	return b
 }
 
-func (b *base) Login() (LoginResponse, error) {
-	result := LoginResponse{}
-	url := fmt.Sprintf("%v/v1/auth/login", b.APIBaseUrl)
+// cachedBanks returns the bank list cached by GetBanksUseCache, or nil if it has not been fetched.
+func (b *base) cachedBanks() *BanksResponse {
+	b.banksMu.RLock()
+	defer b.banksMu.RUnlock()
 
-	rawResponse, statusCode, err := b.postRequest(url, requestAuthTypeBasic, nil)
-	if err != nil {
-		return result, err
-	}
+	return b.banks
+}
 
-	err = b.unmarshallJson(strings.NewReader(rawResponse), &result)
+func (b *base) Login() (LoginResponse, error) {
+	result, err := do[LoginResponse](b, call{method: http.MethodPost, path: "/v1/auth/login", auth: requestAuthTypeBasic})
 	if err != nil {
-		return result, err
+		return LoginResponse{}, err
 	}
 
 	AuthToken = result.ResponseBody.AccessToken
 	t := time.Second * time.Duration(result.ResponseBody.ExpiresIn)
 	ExpiresIn = time.Now().UTC().Add(t)
 
-	if statusCode != http.StatusOK {
-		return result, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-	return result, nil
+	return *result, nil
 }
 
-func (b *base) unmarshallJson(body io.Reader, responseBody interface{}) error {
-	rawResponseBody, err := ioutil.ReadAll(body)
+// do sends the call to Monnify and decodes the response into a new T.
+// Every endpoint goes through it so that requests are encoded, authenticated and checked the same way.
+func do[T any, PT interface {
+	*T
+	responseMeta
+}](b *base, c call) (*T, error) {
+	resp, err := b.send(c)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	err = json.Unmarshal(rawResponseBody, &responseBody)
-	if err != nil {
-		return err
+	result := PT(new(T))
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil && err != io.EOF {
+		if resp.StatusCode != http.StatusOK {
+			return nil, failedRequestMessage(resp.StatusCode, "", http.StatusText(resp.StatusCode))
+		}
+		return nil, err
 	}
 
-	return nil
-}
-
-func (b *base) setBasicAuth(req *http.Request) {
-	s := []byte(fmt.Sprintf("%v:%v", b.Config.APIKey, b.Config.SecretKey))
-	req.Header.Set("Authorization", fmt.Sprintf("Basic %v", base64.StdEncoding.EncodeToString(s)))
-}
-
-func (b *base) setBearerAuth(req *http.Request) {
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", AuthToken))
+	if err := b.checkResponse(resp.StatusCode, result.meta()); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (b *base) isTokenExpired() bool {
-	if time.Now().After(ExpiresIn) {
-		return true
+// checkResponse decides whether a decoded response is a success. Monnify sometimes rejects a request with HTTP 200,
+// flagging it with requestSuccessful false and a non zero responseCode, which is treated as a failure unless
+// Config.AllowUnsuccessfulResponses is set.
+func (b *base) checkResponse(statusCode int, meta *apiResponseMeta) error {
+	if statusCode != http.StatusOK {
+		return failedRequestMessage(statusCode, meta.ResponseCode, meta.ResponseMessage)
 	}
-	return false
+	if b.Config.AllowUnsuccessfulResponses {
+		return nil
+	}
+	if !meta.RequestSuccessful || (meta.ResponseCode != "" && meta.ResponseCode != "0") {
+		return failedRequestMessage(statusCode, meta.ResponseCode, meta.ResponseMessage)
+	}
+	return nil
 }
 
-func (b *base) postRequest(url string, authType requestAuthType, data interface{}) (string, int, error) {
-	var payload io.Reader
-	payload = nil
-
-	if data != nil {
-		p, err := json.Marshal(data)
+// send builds the HTTP request for the call, authenticates it and sends it.
+// When a Bearer token is rejected before ExpiresIn, e.g. because Monnify revoked it early or the clocks are skewed,
+// the client logs in again and replays the request once. A second 401 is returned as is, so a rejected login never loops.
+func (b *base) send(c call) (*http.Response, error) {
+	var payload []byte
+	if c.body != nil {
+		p, err := json.Marshal(c.body)
 		if err != nil {
-			return "", 0, err
+			return nil, err
 		}
-		payload = bytes.NewReader(p)
+		payload = p
 	}
 
-	return b.request("POST", url, authType, payload)
-}
+	resp, err := b.sendOnce(c, payload)
+	if err != nil || c.auth != requestAuthTypeBearer || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
 
-func (b *base) getRequest(url string, authType requestAuthType) (string, int, error) {
-	return b.request("GET", url, authType, nil)
+	resp.Body.Close()
+	ExpiresIn = time.Time{}
+	return b.sendOnce(c, payload)
 }
 
-func (b *base) deleteRequest(url string, authType requestAuthType) (string, int, error) {
-	return b.request("DELETE", url, authType, nil)
-}
+func (b *base) sendOnce(c call, payload []byte) (*http.Response, error) {
+	endpoint := b.APIBaseUrl + c.path
+	if len(c.query) > 0 {
+		endpoint = fmt.Sprintf("%v?%v", endpoint, c.query.Encode())
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
 
-func (b *base) request(method, url string, authType requestAuthType, data io.Reader) (string, int, error) {
-	req, err := http.NewRequest(method, url, data)
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, c.method, endpoint, body)
 	if err != nil {
-		return "", 0, err
+		return nil, err
 	}
 
-	switch authType {
+	switch c.auth {
 	case requestAuthTypeBasic:
 		b.setBasicAuth(req)
 	case requestAuthTypeBearer:
 		if b.isTokenExpired() {
 			_, err := b.Login()
 			if err != nil {
-				return "", 0, err
+				return nil, err
 			}
 		}
 		b.setBearerAuth(req)
@@ -142,20 +157,34 @@ func (b *base) request(method, url string, authType requestAuthType, data io.Rea
 
 	req.Header.Add("Content-Type", "application/json")
 
-	resp, err := b.HTTPClient.Do(req)
-	if err != nil {
-		return "", 0, err
-	}
+	return b.HTTPClient.Do(req)
+}
 
-	if err != nil {
-		return "", 0, err
-	}
-	defer resp.Body.Close()
+func (m *apiResponseMeta) meta() *apiResponseMeta {
+	return m
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", resp.StatusCode, err
+// urlPath formats an endpoint path, escaping every segment so that references cannot alter the path.
+func urlPath(format string, segments ...string) string {
+	escaped := make([]interface{}, len(segments))
+	for i, s := range segments {
+		escaped[i] = url.PathEscape(s)
 	}
+	return fmt.Sprintf(format, escaped...)
+}
 
-	return string(body), resp.StatusCode, nil
+func (b *base) setBasicAuth(req *http.Request) {
+	s := []byte(fmt.Sprintf("%v:%v", b.Config.APIKey, b.Config.SecretKey))
+	req.Header.Set("Authorization", fmt.Sprintf("Basic %v", base64.StdEncoding.EncodeToString(s)))
+}
+
+func (b *base) setBearerAuth(req *http.Request) {
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", AuthToken))
+}
+
+func (b *base) isTokenExpired() bool {
+	if time.Now().After(ExpiresIn) {
+		return true
+	}
+	return false
 }
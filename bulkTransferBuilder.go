@@ -0,0 +1,267 @@
+package gomonnify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+	"sync"
+)
+
+const (
+	// DefaultBulkTransferBatchSize is the number of transfers sent in each batch when BulkTransferBuilder.MaxBatchSize
+	// is not set.
+	DefaultBulkTransferBatchSize = 1000
+
+	// DefaultAccountValidationWorkers is the number of account numbers validated at the same time when
+	// BulkTransferBuilder.Workers is not set.
+	DefaultAccountValidationWorkers = 10
+
+	// BulkTransferItemSubmitted is the outcome of a transfer sent to Monnify in a batch that was accepted.
+	BulkTransferItemSubmitted BulkTransferItemOutcome = "SUBMITTED"
+
+	// BulkTransferItemRejected is the outcome of a transfer that was not sent because it failed the pre-checks: a
+	// duplicate reference, invalid params or an account number that could not be validated.
+	BulkTransferItemRejected BulkTransferItemOutcome = "REJECTED"
+
+	// BulkTransferItemFailed is the outcome of a transfer that could not be sent, because its account number could not
+	// be checked (e.g. the network is down) or its batch was rejected by Monnify.
+	BulkTransferItemFailed BulkTransferItemOutcome = "FAILED"
+
+	// BulkTransferItemAmbiguous is the outcome of a transfer whose batch was sent without a definite answer, e.g. the
+	// connection dropped or Monnify returned a 5xx. The batch may have been accepted: check it with
+	// BulkTransferDetails or AwaitBatchFinalStatus before sending its transfers again.
+	BulkTransferItemAmbiguous BulkTransferItemOutcome = "AMBIGUOUS"
+)
+
+type (
+	// BulkTransferItemOutcome is what happened to a transfer added to a BulkTransferBuilder.
+	BulkTransferItemOutcome string
+
+	// BulkTransferBuilder sends any number of transfers as bulk transfers. Transfers are deduplicated by reference,
+	// checked with ValidateAccountNumber by a pool of Workers and split into batches of at most MaxBatchSize.
+	// Batch holds the title, narration, wallet and options of every batch; its TransactionList is ignored.
+	// When more than one batch is needed, batch references are derived from Batch.BatchReference: REF-1, REF-2...
+	BulkTransferBuilder struct {
+		Disbursements         DisbursementsAPI
+		Batch                 params.BulkTransferParam
+		MaxBatchSize          int
+		Workers               int
+		SkipAccountValidation bool
+
+		items []params.SingleTransferParam
+	}
+
+	// BulkTransferItemResult is the outcome of a single transfer. BatchReference is set once the transfer was put in a
+	// batch, Err holds the reason it was rejected or failed.
+	BulkTransferItemResult struct {
+		Transfer       params.SingleTransferParam
+		Outcome        BulkTransferItemOutcome
+		BatchReference string
+		Err            error
+	}
+
+	// BulkTransferBatchResult is the outcome of a batch sent to Monnify, either its Response or an Err.
+	BulkTransferBatchResult struct {
+		BatchReference string
+		Transfers      int
+		Response       *BulkTransferResponse
+		Err            error
+	}
+
+	// BulkTransferReport combines the outcome of every transfer and batch. Transfers are listed in the order they
+	// were added.
+	BulkTransferReport struct {
+		Batches   []BulkTransferBatchResult
+		Submitted []BulkTransferItemResult
+		Rejected  []BulkTransferItemResult
+		Failed    []BulkTransferItemResult
+		Ambiguous []BulkTransferItemResult
+	}
+)
+
+// NewBulkTransferBuilder returns a BulkTransferBuilder sending batches shaped like batch through d.
+func NewBulkTransferBuilder(d DisbursementsAPI, batch params.BulkTransferParam) *BulkTransferBuilder {
+	return &BulkTransferBuilder{Disbursements: d, Batch: batch}
+}
+
+// Add queues transfers to be sent.
+func (b *BulkTransferBuilder) Add(transfers ...params.SingleTransferParam) *BulkTransferBuilder {
+	b.items = append(b.items, transfers...)
+	return b
+}
+
+// Send checks the queued transfers, sends them in batches and reports what happened to each of them.
+// An error is only returned, before anything is sent, when Batch is invalid. Transfers that could not be checked or
+// sent because ctx is done are reported as failed, and transfers in a batch Monnify gave no definite answer for as
+// ambiguous.
+func (b *BulkTransferBuilder) Send(ctx context.Context) (*BulkTransferReport, error) {
+	if err := b.validateBatch(); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkTransferItemResult, len(b.items))
+	var pending []int
+	seen := map[string]bool{}
+	for i, item := range b.items {
+		results[i] = BulkTransferItemResult{Transfer: item}
+		if err := item.Validate(); err != nil {
+			results[i].Outcome = BulkTransferItemRejected
+			results[i].Err = err
+			continue
+		}
+
+		if seen[item.Reference] {
+			results[i].Outcome = BulkTransferItemRejected
+			results[i].Err = fmt.Errorf("reference %v is duplicated", item.Reference)
+			continue
+		}
+		seen[item.Reference] = true
+		pending = append(pending, i)
+	}
+
+	if !b.SkipAccountValidation {
+		pending = b.validateAccounts(ctx, pending, results)
+	}
+
+	report := &BulkTransferReport{}
+	batchSize := b.batchSize()
+	chunks := (len(pending) + batchSize - 1) / batchSize
+	for c := 0; c < chunks; c++ {
+		end := (c + 1) * batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		indexes := pending[c*batchSize : end]
+
+		batch := b.Batch
+		if chunks > 1 {
+			batch.BatchReference = fmt.Sprintf("%v-%d", b.Batch.BatchReference, c+1)
+		}
+		batch.TransactionList = make([]params.SingleTransferParam, 0, len(indexes))
+		for _, i := range indexes {
+			batch.TransactionList = append(batch.TransactionList, b.items[i])
+		}
+
+		result := BulkTransferBatchResult{BatchReference: batch.BatchReference, Transfers: len(indexes)}
+		outcome := BulkTransferItemFailed
+		if result.Err = ctx.Err(); result.Err == nil {
+			result.Response, result.Err = b.Disbursements.BulkTransfer(batch)
+			if result.Err != nil && !isRejected(result.Err) {
+				outcome = BulkTransferItemAmbiguous
+			}
+		}
+		report.Batches = append(report.Batches, result)
+
+		for _, i := range indexes {
+			results[i].BatchReference = batch.BatchReference
+			results[i].Outcome = BulkTransferItemSubmitted
+			if result.Err != nil {
+				results[i].Outcome = outcome
+				results[i].Err = result.Err
+			}
+		}
+	}
+
+	for _, r := range results {
+		switch r.Outcome {
+		case BulkTransferItemSubmitted:
+			report.Submitted = append(report.Submitted, r)
+		case BulkTransferItemRejected:
+			report.Rejected = append(report.Rejected, r)
+		case BulkTransferItemFailed:
+			report.Failed = append(report.Failed, r)
+		case BulkTransferItemAmbiguous:
+			report.Ambiguous = append(report.Ambiguous, r)
+		}
+	}
+	return report, nil
+}
+
+// validateBatch checks the fields of Batch shared by every batch, including the longest batch reference that may be
+// derived from it. The transfers are checked one by one in Send.
+func (b *BulkTransferBuilder) validateBatch() error {
+	if b.Disbursements == nil {
+		return errors.New("Disbursements is required")
+	}
+
+	batch := b.Batch
+	batch.TransactionList = nil
+	if chunks := (len(b.items) + b.batchSize() - 1) / b.batchSize(); chunks > 1 && b.Batch.BatchReference != "" {
+		batch.BatchReference = fmt.Sprintf("%v-%d", b.Batch.BatchReference, chunks)
+	}
+	err := batch.Validate()
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	var batchErrs ValidationErrors
+	for _, e := range errs {
+		if e.Field == "transactionList" {
+			continue
+		}
+		batchErrs = append(batchErrs, e)
+	}
+	if len(batchErrs) > 0 {
+		return batchErrs
+	}
+	return nil
+}
+
+func (b *BulkTransferBuilder) batchSize() int {
+	if b.MaxBatchSize <= 0 {
+		return DefaultBulkTransferBatchSize
+	}
+	return b.MaxBatchSize
+}
+
+// validateAccounts checks the account number of every pending transfer with a pool of workers, records the
+// transfers that did not pass and returns the ones that did, in order.
+func (b *BulkTransferBuilder) validateAccounts(ctx context.Context, pending []int, results []BulkTransferItemResult) []int {
+	workers := b.Workers
+	if workers <= 0 {
+		workers = DefaultAccountValidationWorkers
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[i].Outcome = BulkTransferItemFailed
+					results[i].Err = err
+					continue
+				}
+
+				item := b.items[i]
+				_, err := b.Disbursements.ValidateAccountNumber(item.AccountNumber, item.BankCode)
+				switch {
+				case err == nil:
+				case isRejected(err):
+					results[i].Outcome = BulkTransferItemRejected
+					results[i].Err = fmt.Errorf("account %v could not be validated: %w", item.AccountNumber, err)
+				default:
+					results[i].Outcome = BulkTransferItemFailed
+					results[i].Err = err
+				}
+			}
+		}()
+	}
+	for _, i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	valid := pending[:0]
+	for _, i := range pending {
+		if results[i].Outcome == "" {
+			valid = append(valid, i)
+		}
+	}
+	return valid
+}
@@ -1,60 +1,47 @@
 package gomonnify
 
 import (
-	"fmt"
+	"errors"
 	"github.com/jcobhams/gomonnify/params"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 // SingleTransfer sends money to a single recipient.
 // Docs: https://docs.teamapt.com/display/MON/Initiate+Transfer
 func (d *disbursements) SingleTransfer(params params.SingleTransferParam) (*SingleTransferResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/single", d.APIBaseUrl)
-	rawResponse, statusCode, err := d.postRequest(url, requestAuthTypeBasic, params)
-	if err != nil {
+	if err := d.validate(params); err != nil {
 		return nil, err
 	}
 
-	result := SingleTransferResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[SingleTransferResponse](d.base, call{
+		method: http.MethodPost,
+		path:   "/v1/disbursements/single",
+		auth:   requestAuthTypeBasic,
+		body:   params,
+	})
 }
 
 // BulkTransfer sends money to a list of recipients.
 // Docs: https://docs.teamapt.com/display/MON/Initiate+Transfer
 func (d *disbursements) BulkTransfer(params params.BulkTransferParam) (*BulkTransferResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/batch", d.APIBaseUrl)
-	rawResponse, statusCode, err := d.postRequest(url, requestAuthTypeBasic, params)
-	if err != nil {
+	if err := d.validate(params); err != nil {
 		return nil, err
 	}
 
-	result := BulkTransferResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[BulkTransferResponse](d.base, call{
+		method: http.MethodPost,
+		path:   "/v1/disbursements/batch",
+		auth:   requestAuthTypeBasic,
+		body:   params,
+	})
 }
 
 // AuthorizeSingleTransfer validates the OTP for the transaction
 // Docs: https://docs.teamapt.com/pages/viewpage.action?pageId=4587995
 func (d *disbursements) AuthorizeSingleTransfer(reference, authorizationCode string) (*SingleTransferResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/single/validate-otp", d.APIBaseUrl)
 	param := struct {
 		Reference         string `json:"reference"`
 		AuthorizationCode string `json:"authorizationCode"`
@@ -62,28 +49,18 @@ func (d *disbursements) AuthorizeSingleTransfer(reference, authorizationCode str
 		Reference:         reference,
 		AuthorizationCode: authorizationCode,
 	}
-	rawResponse, statusCode, err := d.postRequest(url, requestAuthTypeBasic, param)
-	if err != nil {
-		return nil, err
-	}
-
-	result := SingleTransferResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
 
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[SingleTransferResponse](d.base, call{
+		method: http.MethodPost,
+		path:   "/v1/disbursements/single/validate-otp",
+		auth:   requestAuthTypeBasic,
+		body:   param,
+	})
 }
 
 // AuthorizeBulkTransfer validates the OTP for the transaction
 // Docs: https://docs.teamapt.com/pages/viewpage.action?pageId=4587995
 func (d *disbursements) AuthorizeBulkTransfer(reference, authorizationCode string) (*BulkTransferResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/batch/validate-otp", d.APIBaseUrl)
 	param := struct {
 		Reference         string `json:"reference"`
 		AuthorizationCode string `json:"authorizationCode"`
@@ -91,152 +68,106 @@ func (d *disbursements) AuthorizeBulkTransfer(reference, authorizationCode strin
 		Reference:         reference,
 		AuthorizationCode: authorizationCode,
 	}
-	rawResponse, statusCode, err := d.postRequest(url, requestAuthTypeBasic, param)
-	if err != nil {
-		return nil, err
-	}
-	result := BulkTransferResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
 
-	return &result, nil
+	return do[BulkTransferResponse](d.base, call{
+		method: http.MethodPost,
+		path:   "/v1/disbursements/batch/validate-otp",
+		auth:   requestAuthTypeBasic,
+		body:   param,
+	})
 }
 
 // SingleTransferDetails gets a single transfer detail
 // Docs: https://docs.teamapt.com/display/MON/Get+Transfer+Details
 func (d *disbursements) SingleTransferDetails(reference string) (*SingleTransferDetailsResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/single/summary?reference=%v", d.APIBaseUrl, reference)
-
-	rawResponse, statusCode, err := d.getRequest(url, requestAuthTypeBasic)
-	if err != nil {
-		return nil, err
-	}
-
-	result := SingleTransferDetailsResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[SingleTransferDetailsResponse](d.base, call{
+		method: http.MethodGet,
+		path:   "/v1/disbursements/single/summary",
+		query:  url.Values{"reference": {reference}},
+		auth:   requestAuthTypeBasic,
+	})
 }
 
 // BulkTransferDetails gets a bulk transfer detail
 // Docs: https://docs.teamapt.com/display/MON/Get+Transfer+Details
 func (d *disbursements) BulkTransferDetails(batchReference string) (*BulkTransferDetailsResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/batch/summary?reference=%v", d.APIBaseUrl, batchReference)
-	rawResponse, statusCode, err := d.getRequest(url, requestAuthTypeBasic)
-	if err != nil {
-		return nil, err
-	}
-
-	result := BulkTransferDetailsResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[BulkTransferDetailsResponse](d.base, call{
+		method: http.MethodGet,
+		path:   "/v1/disbursements/batch/summary",
+		query:  url.Values{"reference": {batchReference}},
+		auth:   requestAuthTypeBasic,
+	})
 }
 
 // BulkTransferTransactions returns a list of transactions in a bulk transfer batch
 // Docs: https://docs.teamapt.com/display/MON/Get+Bulk+Transfer+Transactions
 func (d *disbursements) BulkTransferTransactions(batchReference string, pageNo, pageSize int) (*TransferTransactionsResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/bulk/%v/transactions?pageNo=%v&pageSize=%v", d.APIBaseUrl, batchReference, pageNo, pageSize)
-	rawResponse, statusCode, err := d.getRequest(url, requestAuthTypeBasic)
-	if err != nil {
-		return nil, err
-	}
-
-	result := TransferTransactionsResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[TransferTransactionsResponse](d.base, call{
+		method: http.MethodGet,
+		path:   urlPath("/v1/disbursements/bulk/%v/transactions", batchReference),
+		query:  pageQuery(pageNo, pageSize),
+		auth:   requestAuthTypeBasic,
+	})
 }
 
 func (d *disbursements) SingleTransferTransactions(pageNo, pageSize int) (*TransferTransactionsResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/single/transactions?pageNo=%v&pageSize=%v", d.APIBaseUrl, pageNo, pageSize)
-	rawResponse, statusCode, err := d.getRequest(url, requestAuthTypeBasic)
-	if err != nil {
-		return nil, err
-	}
-
-	result := TransferTransactionsResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[TransferTransactionsResponse](d.base, call{
+		method: http.MethodGet,
+		path:   "/v1/disbursements/single/transactions",
+		query:  pageQuery(pageNo, pageSize),
+		auth:   requestAuthTypeBasic,
+	})
 }
 
 // ValidateAccountNumber This allows you check if an account number is a valid NUBAN, get the account name if valid.
 // Docs: https://docs.teamapt.com/display/MON/Validate+Bank+Account
 func (d *disbursements) ValidateAccountNumber(accountNumber, bankCode string) (*ValidAccountNumberResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/account/validate?accountNumber=%v&bankCode=%v", d.APIBaseUrl, accountNumber, bankCode)
-	rawResponse, statusCode, err := d.getRequest(url, requestAuthTypeBasic)
-	if err != nil {
-		return nil, err
-	}
-
-	result := ValidAccountNumberResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[ValidAccountNumberResponse](d.base, call{
+		method: http.MethodGet,
+		path:   "/v1/disbursements/account/validate",
+		query:  url.Values{"accountNumber": {accountNumber}, "bankCode": {bankCode}},
+		auth:   requestAuthTypeBasic,
+	})
 }
 
 // WalletBalance returns the available balance in the monnify wallet
 // Docs: https://docs.teamapt.com/display/MON/Get+Wallet+Balance
 func (d *disbursements) WalletBalance(walletId string) (*WalletBalanceResponse, error) {
-	url := fmt.Sprintf("%v/v1/disbursements/wallet-balance?walletId=%v", d.APIBaseUrl, walletId)
-	rawResponse, statusCode, err := d.getRequest(url, requestAuthTypeBasic)
-	if err != nil {
-		return nil, err
+	return do[WalletBalanceResponse](d.base, call{
+		method: http.MethodGet,
+		path:   "/v1/disbursements/wallet-balance",
+		query:  url.Values{"walletId": {walletId}},
+		auth:   requestAuthTypeBasic,
+	})
+}
+
+// WalletStatement returns the ledger entries (credits, debits and fees) posted to the wallet between from and to,
+// together with the running balance before and after each entry. A zero from or to leaves that end of the range open.
+// Docs: https://docs.teamapt.com/display/MON/Get+Wallet+Statement
+func (d *disbursements) WalletStatement(walletId string, from, to time.Time, pageNo, pageSize int) (*WalletStatementResponse, error) {
+	if walletId == "" {
+		return nil, errors.New("walletId is required")
 	}
 
-	result := WalletBalanceResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, errors.New("invalid date range - to is before from")
 	}
 
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
+	query := pageQuery(pageNo, pageSize)
+	query.Set("walletId", walletId)
+	if !from.IsZero() {
+		query.Set("from", strconv.FormatInt(from.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if !to.IsZero() {
+		query.Set("to", strconv.FormatInt(to.UnixNano()/int64(time.Millisecond), 10))
 	}
 
-	return &result, nil
+	return do[WalletStatementResponse](d.base, call{
+		method: http.MethodGet,
+		path:   "/v1/disbursements/wallet/transactions",
+		query:  query,
+		auth:   requestAuthTypeBasic,
+	})
 }
 
 func (d *disbursements) ResendOTP(reference string) (*ResendOTPResponse, error) {
@@ -246,21 +177,15 @@ func (d *disbursements) ResendOTP(reference string) (*ResendOTPResponse, error)
 		Reference: reference,
 	}
 
-	url := fmt.Sprintf("%v/v1/disbursements/single/resend-otp", d.APIBaseUrl)
-	rawResponse, statusCode, err := d.postRequest(url, requestAuthTypeBasic, param)
-	if err != nil {
-		return nil, err
-	}
-
-	result := ResendOTPResponse{}
-	err = d.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
+	return do[ResendOTPResponse](d.base, call{
+		method: http.MethodPost,
+		path:   "/v1/disbursements/single/resend-otp",
+		auth:   requestAuthTypeBasic,
+		body:   param,
+	})
+}
 
-	return &result, nil
+// pageQuery returns the query values of the disbursement endpoints that are paged with pageNo and pageSize.
+func pageQuery(pageNo, pageSize int) url.Values {
+	return url.Values{"pageNo": {strconv.Itoa(pageNo)}, "pageSize": {strconv.Itoa(pageSize)}}
 }
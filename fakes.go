@@ -0,0 +1,867 @@
+// Code generated by fakegen from interfaces.go. DO NOT EDIT.
+
+package gomonnify
+
+import (
+	"context"
+	"github.com/jcobhams/gomonnify/params"
+	"sync"
+	"time"
+)
+
+// FakeGeneral is a programmable fake of GeneralAPI for use in tests.
+// Set the XxxFunc field of every method the code under test calls; calling a method whose func is not set panics.
+// The arguments of every call are recorded and returned by XxxCalls.
+type FakeGeneral struct {
+	LoginFunc                 func() (LoginResponse, error)
+	InitializeTransactionFunc func(param params.InitializeTransactionParam) (*InitializeTransactionResponse, error)
+	VerifyTransactionFunc     func(payload *GeneralTransaction, twoStep bool) bool
+	GetTransactionFunc        func(reference string) (*GeneralTransactionResponse, error)
+	GetBanksFunc              func() (*BanksResponse, error)
+	GetBanksUseCacheFunc      func() (*BanksResponse, error)
+	InvalidateBankCacheFunc   func()
+
+	mu    sync.Mutex
+	calls struct {
+		Login                 []struct{}
+		InitializeTransaction []struct {
+			Param params.InitializeTransactionParam
+		}
+		VerifyTransaction []struct {
+			Payload *GeneralTransaction
+			TwoStep bool
+		}
+		GetTransaction      []struct{ Reference string }
+		GetBanks            []struct{}
+		GetBanksUseCache    []struct{}
+		InvalidateBankCache []struct{}
+	}
+}
+
+var _ GeneralAPI = &FakeGeneral{}
+
+// Login calls LoginFunc.
+func (f *FakeGeneral) Login() (LoginResponse, error) {
+	if f.LoginFunc == nil {
+		panic("gomonnify: FakeGeneral.LoginFunc is nil but GeneralAPI.Login was called")
+	}
+	f.mu.Lock()
+	f.calls.Login = append(f.calls.Login, struct{}{})
+	f.mu.Unlock()
+	return f.LoginFunc()
+}
+
+// LoginCalls returns the arguments of every call made to Login, oldest first.
+func (f *FakeGeneral) LoginCalls() []struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{}(nil), f.calls.Login...)
+}
+
+// InitializeTransaction calls InitializeTransactionFunc.
+func (f *FakeGeneral) InitializeTransaction(param params.InitializeTransactionParam) (*InitializeTransactionResponse, error) {
+	if f.InitializeTransactionFunc == nil {
+		panic("gomonnify: FakeGeneral.InitializeTransactionFunc is nil but GeneralAPI.InitializeTransaction was called")
+	}
+	f.mu.Lock()
+	f.calls.InitializeTransaction = append(f.calls.InitializeTransaction, struct {
+		Param params.InitializeTransactionParam
+	}{Param: param})
+	f.mu.Unlock()
+	return f.InitializeTransactionFunc(param)
+}
+
+// InitializeTransactionCalls returns the arguments of every call made to InitializeTransaction, oldest first.
+func (f *FakeGeneral) InitializeTransactionCalls() []struct {
+	Param params.InitializeTransactionParam
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		Param params.InitializeTransactionParam
+	}(nil), f.calls.InitializeTransaction...)
+}
+
+// VerifyTransaction calls VerifyTransactionFunc.
+func (f *FakeGeneral) VerifyTransaction(payload *GeneralTransaction, twoStep bool) bool {
+	if f.VerifyTransactionFunc == nil {
+		panic("gomonnify: FakeGeneral.VerifyTransactionFunc is nil but GeneralAPI.VerifyTransaction was called")
+	}
+	f.mu.Lock()
+	f.calls.VerifyTransaction = append(f.calls.VerifyTransaction, struct {
+		Payload *GeneralTransaction
+		TwoStep bool
+	}{Payload: payload, TwoStep: twoStep})
+	f.mu.Unlock()
+	return f.VerifyTransactionFunc(payload, twoStep)
+}
+
+// VerifyTransactionCalls returns the arguments of every call made to VerifyTransaction, oldest first.
+func (f *FakeGeneral) VerifyTransactionCalls() []struct {
+	Payload *GeneralTransaction
+	TwoStep bool
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		Payload *GeneralTransaction
+		TwoStep bool
+	}(nil), f.calls.VerifyTransaction...)
+}
+
+// GetTransaction calls GetTransactionFunc.
+func (f *FakeGeneral) GetTransaction(reference string) (*GeneralTransactionResponse, error) {
+	if f.GetTransactionFunc == nil {
+		panic("gomonnify: FakeGeneral.GetTransactionFunc is nil but GeneralAPI.GetTransaction was called")
+	}
+	f.mu.Lock()
+	f.calls.GetTransaction = append(f.calls.GetTransaction, struct{ Reference string }{Reference: reference})
+	f.mu.Unlock()
+	return f.GetTransactionFunc(reference)
+}
+
+// GetTransactionCalls returns the arguments of every call made to GetTransaction, oldest first.
+func (f *FakeGeneral) GetTransactionCalls() []struct{ Reference string } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ Reference string }(nil), f.calls.GetTransaction...)
+}
+
+// GetBanks calls GetBanksFunc.
+func (f *FakeGeneral) GetBanks() (*BanksResponse, error) {
+	if f.GetBanksFunc == nil {
+		panic("gomonnify: FakeGeneral.GetBanksFunc is nil but GeneralAPI.GetBanks was called")
+	}
+	f.mu.Lock()
+	f.calls.GetBanks = append(f.calls.GetBanks, struct{}{})
+	f.mu.Unlock()
+	return f.GetBanksFunc()
+}
+
+// GetBanksCalls returns the arguments of every call made to GetBanks, oldest first.
+func (f *FakeGeneral) GetBanksCalls() []struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{}(nil), f.calls.GetBanks...)
+}
+
+// GetBanksUseCache calls GetBanksUseCacheFunc.
+func (f *FakeGeneral) GetBanksUseCache() (*BanksResponse, error) {
+	if f.GetBanksUseCacheFunc == nil {
+		panic("gomonnify: FakeGeneral.GetBanksUseCacheFunc is nil but GeneralAPI.GetBanksUseCache was called")
+	}
+	f.mu.Lock()
+	f.calls.GetBanksUseCache = append(f.calls.GetBanksUseCache, struct{}{})
+	f.mu.Unlock()
+	return f.GetBanksUseCacheFunc()
+}
+
+// GetBanksUseCacheCalls returns the arguments of every call made to GetBanksUseCache, oldest first.
+func (f *FakeGeneral) GetBanksUseCacheCalls() []struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{}(nil), f.calls.GetBanksUseCache...)
+}
+
+// InvalidateBankCache calls InvalidateBankCacheFunc.
+func (f *FakeGeneral) InvalidateBankCache() {
+	if f.InvalidateBankCacheFunc == nil {
+		panic("gomonnify: FakeGeneral.InvalidateBankCacheFunc is nil but GeneralAPI.InvalidateBankCache was called")
+	}
+	f.mu.Lock()
+	f.calls.InvalidateBankCache = append(f.calls.InvalidateBankCache, struct{}{})
+	f.mu.Unlock()
+	f.InvalidateBankCacheFunc()
+}
+
+// InvalidateBankCacheCalls returns the arguments of every call made to InvalidateBankCache, oldest first.
+func (f *FakeGeneral) InvalidateBankCacheCalls() []struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{}(nil), f.calls.InvalidateBankCache...)
+}
+
+// FakeDisbursements is a programmable fake of DisbursementsAPI for use in tests.
+// Set the XxxFunc field of every method the code under test calls; calling a method whose func is not set panics.
+// The arguments of every call are recorded and returned by XxxCalls.
+type FakeDisbursements struct {
+	SingleTransferFunc             func(param params.SingleTransferParam) (*SingleTransferResponse, error)
+	BulkTransferFunc               func(param params.BulkTransferParam) (*BulkTransferResponse, error)
+	AuthorizeSingleTransferFunc    func(reference string, authorizationCode string) (*SingleTransferResponse, error)
+	AuthorizeBulkTransferFunc      func(reference string, authorizationCode string) (*BulkTransferResponse, error)
+	SingleTransferDetailsFunc      func(reference string) (*SingleTransferDetailsResponse, error)
+	BulkTransferDetailsFunc        func(batchReference string) (*BulkTransferDetailsResponse, error)
+	BulkTransferTransactionsFunc   func(batchReference string, pageNo int, pageSize int) (*TransferTransactionsResponse, error)
+	SingleTransferTransactionsFunc func(pageNo int, pageSize int) (*TransferTransactionsResponse, error)
+	ValidateAccountNumberFunc      func(accountNumber string, bankCode string) (*ValidAccountNumberResponse, error)
+	WalletBalanceFunc              func(walletId string) (*WalletBalanceResponse, error)
+	WalletStatementFunc            func(walletId string, from time.Time, to time.Time, pageNo int, pageSize int) (*WalletStatementResponse, error)
+	ResendOTPFunc                  func(reference string) (*ResendOTPResponse, error)
+	AwaitFinalStatusFunc           func(ctx context.Context, reference string, policy PollPolicy) (*SingleTransferDetails, error)
+	AwaitBatchFinalStatusFunc      func(ctx context.Context, batchReference string, policy PollPolicy) (*BulkTransferDetails, error)
+
+	mu    sync.Mutex
+	calls struct {
+		SingleTransfer          []struct{ Param params.SingleTransferParam }
+		BulkTransfer            []struct{ Param params.BulkTransferParam }
+		AuthorizeSingleTransfer []struct {
+			Reference         string
+			AuthorizationCode string
+		}
+		AuthorizeBulkTransfer []struct {
+			Reference         string
+			AuthorizationCode string
+		}
+		SingleTransferDetails    []struct{ Reference string }
+		BulkTransferDetails      []struct{ BatchReference string }
+		BulkTransferTransactions []struct {
+			BatchReference string
+			PageNo         int
+			PageSize       int
+		}
+		SingleTransferTransactions []struct {
+			PageNo   int
+			PageSize int
+		}
+		ValidateAccountNumber []struct {
+			AccountNumber string
+			BankCode      string
+		}
+		WalletBalance   []struct{ WalletId string }
+		WalletStatement []struct {
+			WalletId string
+			From     time.Time
+			To       time.Time
+			PageNo   int
+			PageSize int
+		}
+		ResendOTP        []struct{ Reference string }
+		AwaitFinalStatus []struct {
+			Ctx       context.Context
+			Reference string
+			Policy    PollPolicy
+		}
+		AwaitBatchFinalStatus []struct {
+			Ctx            context.Context
+			BatchReference string
+			Policy         PollPolicy
+		}
+	}
+}
+
+var _ DisbursementsAPI = &FakeDisbursements{}
+
+// SingleTransfer calls SingleTransferFunc.
+func (f *FakeDisbursements) SingleTransfer(param params.SingleTransferParam) (*SingleTransferResponse, error) {
+	if f.SingleTransferFunc == nil {
+		panic("gomonnify: FakeDisbursements.SingleTransferFunc is nil but DisbursementsAPI.SingleTransfer was called")
+	}
+	f.mu.Lock()
+	f.calls.SingleTransfer = append(f.calls.SingleTransfer, struct{ Param params.SingleTransferParam }{Param: param})
+	f.mu.Unlock()
+	return f.SingleTransferFunc(param)
+}
+
+// SingleTransferCalls returns the arguments of every call made to SingleTransfer, oldest first.
+func (f *FakeDisbursements) SingleTransferCalls() []struct{ Param params.SingleTransferParam } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ Param params.SingleTransferParam }(nil), f.calls.SingleTransfer...)
+}
+
+// BulkTransfer calls BulkTransferFunc.
+func (f *FakeDisbursements) BulkTransfer(param params.BulkTransferParam) (*BulkTransferResponse, error) {
+	if f.BulkTransferFunc == nil {
+		panic("gomonnify: FakeDisbursements.BulkTransferFunc is nil but DisbursementsAPI.BulkTransfer was called")
+	}
+	f.mu.Lock()
+	f.calls.BulkTransfer = append(f.calls.BulkTransfer, struct{ Param params.BulkTransferParam }{Param: param})
+	f.mu.Unlock()
+	return f.BulkTransferFunc(param)
+}
+
+// BulkTransferCalls returns the arguments of every call made to BulkTransfer, oldest first.
+func (f *FakeDisbursements) BulkTransferCalls() []struct{ Param params.BulkTransferParam } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ Param params.BulkTransferParam }(nil), f.calls.BulkTransfer...)
+}
+
+// AuthorizeSingleTransfer calls AuthorizeSingleTransferFunc.
+func (f *FakeDisbursements) AuthorizeSingleTransfer(reference string, authorizationCode string) (*SingleTransferResponse, error) {
+	if f.AuthorizeSingleTransferFunc == nil {
+		panic("gomonnify: FakeDisbursements.AuthorizeSingleTransferFunc is nil but DisbursementsAPI.AuthorizeSingleTransfer was called")
+	}
+	f.mu.Lock()
+	f.calls.AuthorizeSingleTransfer = append(f.calls.AuthorizeSingleTransfer, struct {
+		Reference         string
+		AuthorizationCode string
+	}{Reference: reference, AuthorizationCode: authorizationCode})
+	f.mu.Unlock()
+	return f.AuthorizeSingleTransferFunc(reference, authorizationCode)
+}
+
+// AuthorizeSingleTransferCalls returns the arguments of every call made to AuthorizeSingleTransfer, oldest first.
+func (f *FakeDisbursements) AuthorizeSingleTransferCalls() []struct {
+	Reference         string
+	AuthorizationCode string
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		Reference         string
+		AuthorizationCode string
+	}(nil), f.calls.AuthorizeSingleTransfer...)
+}
+
+// AuthorizeBulkTransfer calls AuthorizeBulkTransferFunc.
+func (f *FakeDisbursements) AuthorizeBulkTransfer(reference string, authorizationCode string) (*BulkTransferResponse, error) {
+	if f.AuthorizeBulkTransferFunc == nil {
+		panic("gomonnify: FakeDisbursements.AuthorizeBulkTransferFunc is nil but DisbursementsAPI.AuthorizeBulkTransfer was called")
+	}
+	f.mu.Lock()
+	f.calls.AuthorizeBulkTransfer = append(f.calls.AuthorizeBulkTransfer, struct {
+		Reference         string
+		AuthorizationCode string
+	}{Reference: reference, AuthorizationCode: authorizationCode})
+	f.mu.Unlock()
+	return f.AuthorizeBulkTransferFunc(reference, authorizationCode)
+}
+
+// AuthorizeBulkTransferCalls returns the arguments of every call made to AuthorizeBulkTransfer, oldest first.
+func (f *FakeDisbursements) AuthorizeBulkTransferCalls() []struct {
+	Reference         string
+	AuthorizationCode string
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		Reference         string
+		AuthorizationCode string
+	}(nil), f.calls.AuthorizeBulkTransfer...)
+}
+
+// SingleTransferDetails calls SingleTransferDetailsFunc.
+func (f *FakeDisbursements) SingleTransferDetails(reference string) (*SingleTransferDetailsResponse, error) {
+	if f.SingleTransferDetailsFunc == nil {
+		panic("gomonnify: FakeDisbursements.SingleTransferDetailsFunc is nil but DisbursementsAPI.SingleTransferDetails was called")
+	}
+	f.mu.Lock()
+	f.calls.SingleTransferDetails = append(f.calls.SingleTransferDetails, struct{ Reference string }{Reference: reference})
+	f.mu.Unlock()
+	return f.SingleTransferDetailsFunc(reference)
+}
+
+// SingleTransferDetailsCalls returns the arguments of every call made to SingleTransferDetails, oldest first.
+func (f *FakeDisbursements) SingleTransferDetailsCalls() []struct{ Reference string } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ Reference string }(nil), f.calls.SingleTransferDetails...)
+}
+
+// BulkTransferDetails calls BulkTransferDetailsFunc.
+func (f *FakeDisbursements) BulkTransferDetails(batchReference string) (*BulkTransferDetailsResponse, error) {
+	if f.BulkTransferDetailsFunc == nil {
+		panic("gomonnify: FakeDisbursements.BulkTransferDetailsFunc is nil but DisbursementsAPI.BulkTransferDetails was called")
+	}
+	f.mu.Lock()
+	f.calls.BulkTransferDetails = append(f.calls.BulkTransferDetails, struct{ BatchReference string }{BatchReference: batchReference})
+	f.mu.Unlock()
+	return f.BulkTransferDetailsFunc(batchReference)
+}
+
+// BulkTransferDetailsCalls returns the arguments of every call made to BulkTransferDetails, oldest first.
+func (f *FakeDisbursements) BulkTransferDetailsCalls() []struct{ BatchReference string } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ BatchReference string }(nil), f.calls.BulkTransferDetails...)
+}
+
+// BulkTransferTransactions calls BulkTransferTransactionsFunc.
+func (f *FakeDisbursements) BulkTransferTransactions(batchReference string, pageNo int, pageSize int) (*TransferTransactionsResponse, error) {
+	if f.BulkTransferTransactionsFunc == nil {
+		panic("gomonnify: FakeDisbursements.BulkTransferTransactionsFunc is nil but DisbursementsAPI.BulkTransferTransactions was called")
+	}
+	f.mu.Lock()
+	f.calls.BulkTransferTransactions = append(f.calls.BulkTransferTransactions, struct {
+		BatchReference string
+		PageNo         int
+		PageSize       int
+	}{BatchReference: batchReference, PageNo: pageNo, PageSize: pageSize})
+	f.mu.Unlock()
+	return f.BulkTransferTransactionsFunc(batchReference, pageNo, pageSize)
+}
+
+// BulkTransferTransactionsCalls returns the arguments of every call made to BulkTransferTransactions, oldest first.
+func (f *FakeDisbursements) BulkTransferTransactionsCalls() []struct {
+	BatchReference string
+	PageNo         int
+	PageSize       int
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		BatchReference string
+		PageNo         int
+		PageSize       int
+	}(nil), f.calls.BulkTransferTransactions...)
+}
+
+// SingleTransferTransactions calls SingleTransferTransactionsFunc.
+func (f *FakeDisbursements) SingleTransferTransactions(pageNo int, pageSize int) (*TransferTransactionsResponse, error) {
+	if f.SingleTransferTransactionsFunc == nil {
+		panic("gomonnify: FakeDisbursements.SingleTransferTransactionsFunc is nil but DisbursementsAPI.SingleTransferTransactions was called")
+	}
+	f.mu.Lock()
+	f.calls.SingleTransferTransactions = append(f.calls.SingleTransferTransactions, struct {
+		PageNo   int
+		PageSize int
+	}{PageNo: pageNo, PageSize: pageSize})
+	f.mu.Unlock()
+	return f.SingleTransferTransactionsFunc(pageNo, pageSize)
+}
+
+// SingleTransferTransactionsCalls returns the arguments of every call made to SingleTransferTransactions, oldest first.
+func (f *FakeDisbursements) SingleTransferTransactionsCalls() []struct {
+	PageNo   int
+	PageSize int
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		PageNo   int
+		PageSize int
+	}(nil), f.calls.SingleTransferTransactions...)
+}
+
+// ValidateAccountNumber calls ValidateAccountNumberFunc.
+func (f *FakeDisbursements) ValidateAccountNumber(accountNumber string, bankCode string) (*ValidAccountNumberResponse, error) {
+	if f.ValidateAccountNumberFunc == nil {
+		panic("gomonnify: FakeDisbursements.ValidateAccountNumberFunc is nil but DisbursementsAPI.ValidateAccountNumber was called")
+	}
+	f.mu.Lock()
+	f.calls.ValidateAccountNumber = append(f.calls.ValidateAccountNumber, struct {
+		AccountNumber string
+		BankCode      string
+	}{AccountNumber: accountNumber, BankCode: bankCode})
+	f.mu.Unlock()
+	return f.ValidateAccountNumberFunc(accountNumber, bankCode)
+}
+
+// ValidateAccountNumberCalls returns the arguments of every call made to ValidateAccountNumber, oldest first.
+func (f *FakeDisbursements) ValidateAccountNumberCalls() []struct {
+	AccountNumber string
+	BankCode      string
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		AccountNumber string
+		BankCode      string
+	}(nil), f.calls.ValidateAccountNumber...)
+}
+
+// WalletBalance calls WalletBalanceFunc.
+func (f *FakeDisbursements) WalletBalance(walletId string) (*WalletBalanceResponse, error) {
+	if f.WalletBalanceFunc == nil {
+		panic("gomonnify: FakeDisbursements.WalletBalanceFunc is nil but DisbursementsAPI.WalletBalance was called")
+	}
+	f.mu.Lock()
+	f.calls.WalletBalance = append(f.calls.WalletBalance, struct{ WalletId string }{WalletId: walletId})
+	f.mu.Unlock()
+	return f.WalletBalanceFunc(walletId)
+}
+
+// WalletBalanceCalls returns the arguments of every call made to WalletBalance, oldest first.
+func (f *FakeDisbursements) WalletBalanceCalls() []struct{ WalletId string } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ WalletId string }(nil), f.calls.WalletBalance...)
+}
+
+// WalletStatement calls WalletStatementFunc.
+func (f *FakeDisbursements) WalletStatement(walletId string, from time.Time, to time.Time, pageNo int, pageSize int) (*WalletStatementResponse, error) {
+	if f.WalletStatementFunc == nil {
+		panic("gomonnify: FakeDisbursements.WalletStatementFunc is nil but DisbursementsAPI.WalletStatement was called")
+	}
+	f.mu.Lock()
+	f.calls.WalletStatement = append(f.calls.WalletStatement, struct {
+		WalletId string
+		From     time.Time
+		To       time.Time
+		PageNo   int
+		PageSize int
+	}{WalletId: walletId, From: from, To: to, PageNo: pageNo, PageSize: pageSize})
+	f.mu.Unlock()
+	return f.WalletStatementFunc(walletId, from, to, pageNo, pageSize)
+}
+
+// WalletStatementCalls returns the arguments of every call made to WalletStatement, oldest first.
+func (f *FakeDisbursements) WalletStatementCalls() []struct {
+	WalletId string
+	From     time.Time
+	To       time.Time
+	PageNo   int
+	PageSize int
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		WalletId string
+		From     time.Time
+		To       time.Time
+		PageNo   int
+		PageSize int
+	}(nil), f.calls.WalletStatement...)
+}
+
+// ResendOTP calls ResendOTPFunc.
+func (f *FakeDisbursements) ResendOTP(reference string) (*ResendOTPResponse, error) {
+	if f.ResendOTPFunc == nil {
+		panic("gomonnify: FakeDisbursements.ResendOTPFunc is nil but DisbursementsAPI.ResendOTP was called")
+	}
+	f.mu.Lock()
+	f.calls.ResendOTP = append(f.calls.ResendOTP, struct{ Reference string }{Reference: reference})
+	f.mu.Unlock()
+	return f.ResendOTPFunc(reference)
+}
+
+// ResendOTPCalls returns the arguments of every call made to ResendOTP, oldest first.
+func (f *FakeDisbursements) ResendOTPCalls() []struct{ Reference string } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ Reference string }(nil), f.calls.ResendOTP...)
+}
+
+// AwaitFinalStatus calls AwaitFinalStatusFunc.
+func (f *FakeDisbursements) AwaitFinalStatus(ctx context.Context, reference string, policy PollPolicy) (*SingleTransferDetails, error) {
+	if f.AwaitFinalStatusFunc == nil {
+		panic("gomonnify: FakeDisbursements.AwaitFinalStatusFunc is nil but DisbursementsAPI.AwaitFinalStatus was called")
+	}
+	f.mu.Lock()
+	f.calls.AwaitFinalStatus = append(f.calls.AwaitFinalStatus, struct {
+		Ctx       context.Context
+		Reference string
+		Policy    PollPolicy
+	}{Ctx: ctx, Reference: reference, Policy: policy})
+	f.mu.Unlock()
+	return f.AwaitFinalStatusFunc(ctx, reference, policy)
+}
+
+// AwaitFinalStatusCalls returns the arguments of every call made to AwaitFinalStatus, oldest first.
+func (f *FakeDisbursements) AwaitFinalStatusCalls() []struct {
+	Ctx       context.Context
+	Reference string
+	Policy    PollPolicy
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		Ctx       context.Context
+		Reference string
+		Policy    PollPolicy
+	}(nil), f.calls.AwaitFinalStatus...)
+}
+
+// AwaitBatchFinalStatus calls AwaitBatchFinalStatusFunc.
+func (f *FakeDisbursements) AwaitBatchFinalStatus(ctx context.Context, batchReference string, policy PollPolicy) (*BulkTransferDetails, error) {
+	if f.AwaitBatchFinalStatusFunc == nil {
+		panic("gomonnify: FakeDisbursements.AwaitBatchFinalStatusFunc is nil but DisbursementsAPI.AwaitBatchFinalStatus was called")
+	}
+	f.mu.Lock()
+	f.calls.AwaitBatchFinalStatus = append(f.calls.AwaitBatchFinalStatus, struct {
+		Ctx            context.Context
+		BatchReference string
+		Policy         PollPolicy
+	}{Ctx: ctx, BatchReference: batchReference, Policy: policy})
+	f.mu.Unlock()
+	return f.AwaitBatchFinalStatusFunc(ctx, batchReference, policy)
+}
+
+// AwaitBatchFinalStatusCalls returns the arguments of every call made to AwaitBatchFinalStatus, oldest first.
+func (f *FakeDisbursements) AwaitBatchFinalStatusCalls() []struct {
+	Ctx            context.Context
+	BatchReference string
+	Policy         PollPolicy
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		Ctx            context.Context
+		BatchReference string
+		Policy         PollPolicy
+	}(nil), f.calls.AwaitBatchFinalStatus...)
+}
+
+// FakeReservedAccounts is a programmable fake of ReservedAccountsAPI for use in tests.
+// Set the XxxFunc field of every method the code under test calls; calling a method whose func is not set panics.
+// The arguments of every call are recorded and returned by XxxCalls.
+type FakeReservedAccounts struct {
+	ReserveAccountFunc func(param params.ReserveAccountParam) (*ReserveAccountResponse, error)
+	DetailsFunc        func(accountReference string) (*ReserveAccountResponse, error)
+	DeallocateFunc     func(accountNumber string) error
+	TransactionsFunc   func(accountReference string, page int, size int) (*ReservedAccountTransactionsResponse, error)
+
+	mu    sync.Mutex
+	calls struct {
+		ReserveAccount []struct{ Param params.ReserveAccountParam }
+		Details        []struct{ AccountReference string }
+		Deallocate     []struct{ AccountNumber string }
+		Transactions   []struct {
+			AccountReference string
+			Page             int
+			Size             int
+		}
+	}
+}
+
+var _ ReservedAccountsAPI = &FakeReservedAccounts{}
+
+// ReserveAccount calls ReserveAccountFunc.
+func (f *FakeReservedAccounts) ReserveAccount(param params.ReserveAccountParam) (*ReserveAccountResponse, error) {
+	if f.ReserveAccountFunc == nil {
+		panic("gomonnify: FakeReservedAccounts.ReserveAccountFunc is nil but ReservedAccountsAPI.ReserveAccount was called")
+	}
+	f.mu.Lock()
+	f.calls.ReserveAccount = append(f.calls.ReserveAccount, struct{ Param params.ReserveAccountParam }{Param: param})
+	f.mu.Unlock()
+	return f.ReserveAccountFunc(param)
+}
+
+// ReserveAccountCalls returns the arguments of every call made to ReserveAccount, oldest first.
+func (f *FakeReservedAccounts) ReserveAccountCalls() []struct{ Param params.ReserveAccountParam } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ Param params.ReserveAccountParam }(nil), f.calls.ReserveAccount...)
+}
+
+// Details calls DetailsFunc.
+func (f *FakeReservedAccounts) Details(accountReference string) (*ReserveAccountResponse, error) {
+	if f.DetailsFunc == nil {
+		panic("gomonnify: FakeReservedAccounts.DetailsFunc is nil but ReservedAccountsAPI.Details was called")
+	}
+	f.mu.Lock()
+	f.calls.Details = append(f.calls.Details, struct{ AccountReference string }{AccountReference: accountReference})
+	f.mu.Unlock()
+	return f.DetailsFunc(accountReference)
+}
+
+// DetailsCalls returns the arguments of every call made to Details, oldest first.
+func (f *FakeReservedAccounts) DetailsCalls() []struct{ AccountReference string } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ AccountReference string }(nil), f.calls.Details...)
+}
+
+// Deallocate calls DeallocateFunc.
+func (f *FakeReservedAccounts) Deallocate(accountNumber string) error {
+	if f.DeallocateFunc == nil {
+		panic("gomonnify: FakeReservedAccounts.DeallocateFunc is nil but ReservedAccountsAPI.Deallocate was called")
+	}
+	f.mu.Lock()
+	f.calls.Deallocate = append(f.calls.Deallocate, struct{ AccountNumber string }{AccountNumber: accountNumber})
+	f.mu.Unlock()
+	return f.DeallocateFunc(accountNumber)
+}
+
+// DeallocateCalls returns the arguments of every call made to Deallocate, oldest first.
+func (f *FakeReservedAccounts) DeallocateCalls() []struct{ AccountNumber string } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ AccountNumber string }(nil), f.calls.Deallocate...)
+}
+
+// Transactions calls TransactionsFunc.
+func (f *FakeReservedAccounts) Transactions(accountReference string, page int, size int) (*ReservedAccountTransactionsResponse, error) {
+	if f.TransactionsFunc == nil {
+		panic("gomonnify: FakeReservedAccounts.TransactionsFunc is nil but ReservedAccountsAPI.Transactions was called")
+	}
+	f.mu.Lock()
+	f.calls.Transactions = append(f.calls.Transactions, struct {
+		AccountReference string
+		Page             int
+		Size             int
+	}{AccountReference: accountReference, Page: page, Size: size})
+	f.mu.Unlock()
+	return f.TransactionsFunc(accountReference, page, size)
+}
+
+// TransactionsCalls returns the arguments of every call made to Transactions, oldest first.
+func (f *FakeReservedAccounts) TransactionsCalls() []struct {
+	AccountReference string
+	Page             int
+	Size             int
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		AccountReference string
+		Page             int
+		Size             int
+	}(nil), f.calls.Transactions...)
+}
+
+// FakeVerification is a programmable fake of VerificationAPI for use in tests.
+// Set the XxxFunc field of every method the code under test calls; calling a method whose func is not set panics.
+// The arguments of every call are recorded and returned by XxxCalls.
+type FakeVerification struct {
+	MatchBVNAndAccountFunc func(param params.BVNAccountMatchParam) (*BVNAccountMatchResponse, error)
+	VerifyBVNFunc          func(param params.BVNDetailsParam) (*BVNDetailsResponse, error)
+
+	mu    sync.Mutex
+	calls struct {
+		MatchBVNAndAccount []struct{ Param params.BVNAccountMatchParam }
+		VerifyBVN          []struct{ Param params.BVNDetailsParam }
+	}
+}
+
+var _ VerificationAPI = &FakeVerification{}
+
+// MatchBVNAndAccount calls MatchBVNAndAccountFunc.
+func (f *FakeVerification) MatchBVNAndAccount(param params.BVNAccountMatchParam) (*BVNAccountMatchResponse, error) {
+	if f.MatchBVNAndAccountFunc == nil {
+		panic("gomonnify: FakeVerification.MatchBVNAndAccountFunc is nil but VerificationAPI.MatchBVNAndAccount was called")
+	}
+	f.mu.Lock()
+	f.calls.MatchBVNAndAccount = append(f.calls.MatchBVNAndAccount, struct{ Param params.BVNAccountMatchParam }{Param: param})
+	f.mu.Unlock()
+	return f.MatchBVNAndAccountFunc(param)
+}
+
+// MatchBVNAndAccountCalls returns the arguments of every call made to MatchBVNAndAccount, oldest first.
+func (f *FakeVerification) MatchBVNAndAccountCalls() []struct{ Param params.BVNAccountMatchParam } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ Param params.BVNAccountMatchParam }(nil), f.calls.MatchBVNAndAccount...)
+}
+
+// VerifyBVN calls VerifyBVNFunc.
+func (f *FakeVerification) VerifyBVN(param params.BVNDetailsParam) (*BVNDetailsResponse, error) {
+	if f.VerifyBVNFunc == nil {
+		panic("gomonnify: FakeVerification.VerifyBVNFunc is nil but VerificationAPI.VerifyBVN was called")
+	}
+	f.mu.Lock()
+	f.calls.VerifyBVN = append(f.calls.VerifyBVN, struct{ Param params.BVNDetailsParam }{Param: param})
+	f.mu.Unlock()
+	return f.VerifyBVNFunc(param)
+}
+
+// VerifyBVNCalls returns the arguments of every call made to VerifyBVN, oldest first.
+func (f *FakeVerification) VerifyBVNCalls() []struct{ Param params.BVNDetailsParam } {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct{ Param params.BVNDetailsParam }(nil), f.calls.VerifyBVN...)
+}
+
+// FakeWallets is a programmable fake of WalletsAPI for use in tests.
+// Set the XxxFunc field of every method the code under test calls; calling a method whose func is not set panics.
+// The arguments of every call are recorded and returned by XxxCalls.
+type FakeWallets struct {
+	CreateFunc func(walletReference string, walletName string, customer params.WalletCustomerParam) (*WalletResponse, error)
+	ListFunc   func(customerEmail string, pageNo int, pageSize int) (*WalletsResponse, error)
+
+	mu    sync.Mutex
+	calls struct {
+		Create []struct {
+			WalletReference string
+			WalletName      string
+			Customer        params.WalletCustomerParam
+		}
+		List []struct {
+			CustomerEmail string
+			PageNo        int
+			PageSize      int
+		}
+	}
+}
+
+var _ WalletsAPI = &FakeWallets{}
+
+// Create calls CreateFunc.
+func (f *FakeWallets) Create(walletReference string, walletName string, customer params.WalletCustomerParam) (*WalletResponse, error) {
+	if f.CreateFunc == nil {
+		panic("gomonnify: FakeWallets.CreateFunc is nil but WalletsAPI.Create was called")
+	}
+	f.mu.Lock()
+	f.calls.Create = append(f.calls.Create, struct {
+		WalletReference string
+		WalletName      string
+		Customer        params.WalletCustomerParam
+	}{WalletReference: walletReference, WalletName: walletName, Customer: customer})
+	f.mu.Unlock()
+	return f.CreateFunc(walletReference, walletName, customer)
+}
+
+// CreateCalls returns the arguments of every call made to Create, oldest first.
+func (f *FakeWallets) CreateCalls() []struct {
+	WalletReference string
+	WalletName      string
+	Customer        params.WalletCustomerParam
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		WalletReference string
+		WalletName      string
+		Customer        params.WalletCustomerParam
+	}(nil), f.calls.Create...)
+}
+
+// List calls ListFunc.
+func (f *FakeWallets) List(customerEmail string, pageNo int, pageSize int) (*WalletsResponse, error) {
+	if f.ListFunc == nil {
+		panic("gomonnify: FakeWallets.ListFunc is nil but WalletsAPI.List was called")
+	}
+	f.mu.Lock()
+	f.calls.List = append(f.calls.List, struct {
+		CustomerEmail string
+		PageNo        int
+		PageSize      int
+	}{CustomerEmail: customerEmail, PageNo: pageNo, PageSize: pageSize})
+	f.mu.Unlock()
+	return f.ListFunc(customerEmail, pageNo, pageSize)
+}
+
+// ListCalls returns the arguments of every call made to List, oldest first.
+func (f *FakeWallets) ListCalls() []struct {
+	CustomerEmail string
+	PageNo        int
+	PageSize      int
+} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]struct {
+		CustomerEmail string
+		PageNo        int
+		PageSize      int
+	}(nil), f.calls.List...)
+}
@@ -2,21 +2,41 @@ package gomonnify
 
 import (
 	"crypto/sha512"
+	"encoding/json"
 	"fmt"
+	"github.com/jcobhams/gomonnify/params"
 	"net/http"
 	"strings"
 )
 
+// InitializeTransaction starts a collection and returns the checkout url the customer should be sent to pay.
+// Anything set in params.MetaData is attached to the transaction and returned on it by GetTransaction and notifications.
+// Docs: https://docs.teamapt.com/display/MON/Initialize+Transaction
+func (g *general) InitializeTransaction(params params.InitializeTransactionParam) (*InitializeTransactionResponse, error) {
+	if params.ContractCode == "" {
+		params.ContractCode = g.Config.DefaultContractCode
+	}
+	if params.CurrencyCode == "" {
+		params.CurrencyCode = CurrencyNGN
+	}
+	if err := g.validate(params); err != nil {
+		return nil, err
+	}
+
+	return do[InitializeTransactionResponse](g.base, call{
+		method: http.MethodPost,
+		path:   "/v1/merchant/transactions/init-transaction",
+		auth:   requestAuthTypeBasic,
+		body:   params,
+	})
+}
+
 // VerifyTransaction validates that the payload received is actually from monnify. It computes the transaction hash and compares.
 // twoStep if set to true will make a request to monnify to confirm that the transaction exists and was successful
 // we cannot implement 3 step verification without some knowledge of your persistence layer. Need it? Open an Issue/PR :)
 // Docs: https://docs.teamapt.com/pages/viewpage.action?pageId=13828139
 func (g *general) VerifyTransaction(payload *GeneralTransaction, twoStep bool) bool {
-	rawStr := fmt.Sprintf("%v|%v|%v|%v|%v", g.Config.SecretKey, payload.PaymentReference, payload.AmountPaid, payload.PaidOn, payload.TransactionReference)
-	h := sha512.New()
-	h.Write([]byte(rawStr))
-	hashed := fmt.Sprintf("%x", h.Sum(nil))
-	if hashed != payload.TransactionHash {
+	if !g.validTransactionHash(payload) {
 		return false
 	}
 
@@ -35,67 +55,90 @@ func (g *general) VerifyTransaction(payload *GeneralTransaction, twoStep bool) b
 	return true
 }
 
-// GetTransaction retrieves the transaction specified by reference from the Monnify API.
-// Docs: https://docs.teamapt.com/display/MON/Get+Transaction+Status
-func (g *general) GetTransaction(reference string) (*GeneralTransactionResponse, error) {
-	url := fmt.Sprintf("%v/v2/transactions/%v", g.APIBaseUrl, reference)
-	rawResponse, statusCode, err := g.getRequest(url, requestAuthTypeBearer)
-	if err != nil {
-		fmt.Println(err)
-		return nil, err
+// validTransactionHash computes the transaction hash and compares it with the one in the payload. The amount paid is
+// hashed exactly as Monnify sent it, see GeneralTransaction.AmountPaidRaw.
+func (g *general) validTransactionHash(payload *GeneralTransaction) bool {
+	rawStr := fmt.Sprintf("%v|%v|%v|%v|%v", g.Config.SecretKey, payload.PaymentReference, payload.AmountPaidRaw(), payload.PaidOn.Raw(), payload.TransactionReference)
+	h := sha512.New()
+	h.Write([]byte(rawStr))
+	return fmt.Sprintf("%x", h.Sum(nil)) == payload.TransactionHash
+}
+
+// AmountPaidRaw returns the amount paid as Monnify sent it, e.g. 100, 100.0 or 100.00, which is what the transaction
+// hash is computed from. When the transaction was not decoded from JSON, or AmountPaid was changed since, AmountPaid is
+// formatted with two decimal places instead.
+func (t GeneralTransaction) AmountPaidRaw() string {
+	if t.amountPaidRaw != "" {
+		if a, err := params.ParseAmount(t.amountPaidRaw); err == nil && a == t.AmountPaid {
+			return t.amountPaidRaw
+		}
 	}
+	return t.AmountPaid.String()
+}
 
-	result := GeneralTransactionResponse{}
-	err = g.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
+// UnmarshalJSON implements json.Unmarshaler. It keeps the amount paid as it was sent, see AmountPaidRaw.
+func (t *GeneralTransaction) UnmarshalJSON(data []byte) error {
+	type generalTransaction GeneralTransaction
+	if err := json.Unmarshal(data, (*generalTransaction)(t)); err != nil {
+		return err
 	}
 
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
+	var raw struct {
+		AmountPaid json.RawMessage `json:"amountPaid"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	t.amountPaidRaw = string(raw.AmountPaid)
+	if strings.HasPrefix(t.amountPaidRaw, `"`) {
+		if err := json.Unmarshal(raw.AmountPaid, &t.amountPaidRaw); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return &result, nil
+// GetTransaction retrieves the transaction specified by reference from the Monnify API.
+// Docs: https://docs.teamapt.com/display/MON/Get+Transaction+Status
+func (g *general) GetTransaction(reference string) (*GeneralTransactionResponse, error) {
+	return do[GeneralTransactionResponse](g.base, call{
+		method: http.MethodGet,
+		path:   urlPath("/v2/transactions/%v", reference),
+		auth:   requestAuthTypeBearer,
+	})
 }
 
 // GetBanks fetches a list of banks and their USSD codes from the monnify api.
 // Docs: https://docs.teamapt.com/display/MON/Get+Banks
 func (g *general) GetBanks() (*BanksResponse, error) {
-	url := fmt.Sprintf("%v/v1/banks", g.APIBaseUrl)
-	rawResponse, statusCode, err := g.getRequest(url, requestAuthTypeBearer)
-	if err != nil {
-		return nil, err
-	}
-
-	result := BanksResponse{}
-	err = g.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[BanksResponse](g.base, call{
+		method: http.MethodGet,
+		path:   "/v1/banks",
+		auth:   requestAuthTypeBearer,
+	})
 }
 
 // GetBanksUseCache checks if the bank list is already in the struct
 // and simply reuses that instead of making a HTTP call. If the list is not already in the struct, it fetches and saves
 // a copy to the struct for future use.
+// Once cached, the list is also used to check bank codes in params before they are sent.
 func (g *general) GetBanksUseCache() (*BanksResponse, error) {
-	if g.banks != nil {
-		return g.banks, nil
+	if banks := g.cachedBanks(); banks != nil {
+		return banks, nil
 	}
 	b, err := g.GetBanks()
 	if err != nil {
 		return nil, err
 	}
+	g.banksMu.Lock()
 	g.banks = b
+	g.banksMu.Unlock()
 	return b, nil
 }
 
 // InvalidateBankCache empties the cache from the struct so the next call will make an API Call
 func (g *general) InvalidateBankCache() {
+	g.banksMu.Lock()
 	g.banks = nil
+	g.banksMu.Unlock()
 }
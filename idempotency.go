@@ -0,0 +1,382 @@
+package gomonnify
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// ReferencePending is the state of a reference recorded before its transfer is sent. It stays pending until
+	// Monnify is known to have the transfer, so an interrupted submission is resolved on the next call.
+	ReferencePending ReferenceState = "PENDING"
+
+	// ReferenceSubmitted is the state of a reference whose transfer Monnify has accepted.
+	ReferenceSubmitted ReferenceState = "SUBMITTED"
+
+	// DefaultReferenceClaimTimeout is how long a pending reference stays claimed by the call sending it when
+	// IdempotentTransfers.ClaimTimeout is not set.
+	DefaultReferenceClaimTimeout = 5 * time.Minute
+
+	defaultIdempotentAttempts = 3
+)
+
+var (
+	// ErrReferenceConflict is returned when a reference already recorded for a transfer is reused for a different one.
+	ErrReferenceConflict = errors.New("reference is already used by a different transfer")
+
+	// ErrReferenceInFlight is wrapped in the AmbiguousTransferError returned when another call is still sending the
+	// transfer of a reference.
+	ErrReferenceInFlight = errors.New("transfer is being sent by another call")
+
+	// ErrSubmittedTransferNotFound is wrapped in the AmbiguousTransferError returned when Monnify has no record of a
+	// transfer it already accepted. The transfer is never sent again, as that could pay it twice.
+	ErrSubmittedTransferNotFound = errors.New("transfer was accepted but cannot be found")
+)
+
+type (
+	// ReferenceState is the state of a reference recorded in a ReferenceStore.
+	ReferenceState string
+
+	// ReferenceRecord is what a ReferenceStore keeps about a transfer reference. Fingerprint identifies the transfer
+	// (amount, currency, bank code and account number) so a reused reference can be detected.
+	// Owner identifies the call sending the transfer of a pending reference, from ClaimedAt. It is empty once that call
+	// returned.
+	ReferenceRecord struct {
+		Reference   string
+		Fingerprint string
+		State       ReferenceState
+		Owner       string
+		ClaimedAt   time.Time
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+	}
+
+	// ReferenceStore records transfer references before they are sent. Implementations must be safe for concurrent
+	// use, and should be persistent so that a submission interrupted by a crash can be resolved after a restart.
+	ReferenceStore interface {
+		// Create records a new reference. It returns false, without changing anything, if it is already recorded.
+		Create(record ReferenceRecord) (bool, error)
+		// Get returns the record of reference and whether it exists.
+		Get(reference string) (ReferenceRecord, bool, error)
+		// Update replaces the record of an existing reference.
+		Update(record ReferenceRecord) error
+		// Claim sets the Owner and ClaimedAt of a pending reference to those of record, if it has no owner or was
+		// claimed before staleBefore. It returns false, without changing anything, if the reference is claimed more
+		// recently, is not pending or is not recorded. The check and the change must be atomic.
+		Claim(record ReferenceRecord, staleBefore time.Time) (bool, error)
+		// Release clears the Owner and ClaimedAt of a pending reference claimed by owner. It returns false, without
+		// changing anything, if the reference is claimed by someone else, is not pending or is not recorded. The check
+		// and the change must be atomic.
+		Release(reference, owner string) (bool, error)
+		// Delete removes the record of a pending reference claimed by owner, so that it can be used again. It returns
+		// false, without changing anything, if the reference is claimed by someone else, is not pending or is not
+		// recorded. The check and the change must be atomic.
+		Delete(reference, owner string) (bool, error)
+	}
+
+	// AmbiguousTransferError is returned when it cannot be established whether Monnify received a transfer, e.g. the
+	// network is down. The reference stays pending and calling SingleTransfer again with the same params resolves it.
+	AmbiguousTransferError struct {
+		Reference string
+		Err       error
+	}
+
+	// IdempotentTransfers sends single transfers at most once per reference. The reference is recorded in the store
+	// before the transfer is sent. After an ambiguous failure (network error, timeout or 5xx) the transfer details are
+	// queried and the transfer is only sent again if Monnify has no record of it.
+	// MaxAttempts bounds the number of times a transfer is sent in a single call, defaults to 3.
+	//
+	// The call sending a transfer claims its reference until it returns, so that concurrent calls for the same reference
+	// return an AmbiguousTransferError wrapping ErrReferenceInFlight instead of sending it again. A claim older than
+	// ClaimTimeout is considered abandoned, e.g. the process crashed, and is taken over. ClaimTimeout should exceed the
+	// longest a call can take and defaults to DefaultReferenceClaimTimeout.
+	IdempotentTransfers struct {
+		Disbursements DisbursementsAPI
+		Store         ReferenceStore
+		MaxAttempts   int
+		ClaimTimeout  time.Duration
+	}
+
+	// MemoryReferenceStore is a ReferenceStore that keeps the records in memory. It does not survive a restart, and is
+	// meant for tests and single process setups.
+	MemoryReferenceStore struct {
+		mu      sync.Mutex
+		records map[string]ReferenceRecord
+	}
+)
+
+func (e *AmbiguousTransferError) Error() string {
+	return fmt.Sprintf("unable to establish whether transfer %v was received: %v", e.Reference, e.Err)
+}
+
+func (e *AmbiguousTransferError) Unwrap() error {
+	return e.Err
+}
+
+// NewIdempotentTransfers returns an IdempotentTransfers sending transfers through d and recording them in store.
+func NewIdempotentTransfers(d DisbursementsAPI, store ReferenceStore) *IdempotentTransfers {
+	return &IdempotentTransfers{
+		Disbursements: d,
+		Store:         store,
+		MaxAttempts:   defaultIdempotentAttempts,
+		ClaimTimeout:  DefaultReferenceClaimTimeout,
+	}
+}
+
+// SingleTransfer sends the transfer unless its reference has already been sent, in which case the transfer Monnify
+// has is returned. It returns ErrReferenceConflict if the reference was recorded for a different transfer and an
+// *AmbiguousTransferError if it could not be established whether the transfer was received, or another call is still
+// sending it.
+func (t *IdempotentTransfers) SingleTransfer(param params.SingleTransferParam) (*SingleTransferResponse, error) {
+	if err := param.Validate(); err != nil {
+		return nil, err
+	}
+
+	owner, err := newClaimOwner()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	record := ReferenceRecord{
+		Reference:   param.Reference,
+		Fingerprint: transferFingerprint(param),
+		State:       ReferencePending,
+		Owner:       owner,
+		ClaimedAt:   now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	created, err := t.Store.Create(record)
+	if err != nil {
+		return nil, err
+	}
+
+	// A reference that is already recorded was either sent, is being sent by another call or was interrupted before
+	// its outcome was known. Unless another call holds it, Monnify is asked first.
+	ambiguous := !created
+	if !created {
+		existing, found, err := t.Store.Get(param.Reference)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !found:
+			// The reference was released, by a rejection, since it was found recorded.
+			if created, err = t.Store.Create(record); err != nil {
+				return nil, err
+			}
+			if !created {
+				return nil, &AmbiguousTransferError{Reference: param.Reference, Err: ErrReferenceInFlight}
+			}
+		case existing.Fingerprint != record.Fingerprint:
+			return nil, ErrReferenceConflict
+		case existing.State == ReferencePending:
+			claim := existing
+			claim.Owner, claim.ClaimedAt = owner, now
+			claimed, err := t.Store.Claim(claim, now.Add(-t.claimTimeout()))
+			if err != nil {
+				return nil, err
+			}
+			if !claimed {
+				return nil, &AmbiguousTransferError{Reference: param.Reference, Err: ErrReferenceInFlight}
+			}
+			record = claim
+		default:
+			record = existing
+		}
+	}
+	if record.State == ReferencePending {
+		defer t.release(record)
+	}
+
+	maxAttempts := t.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultIdempotentAttempts
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if ambiguous {
+			details, err := t.Disbursements.SingleTransferDetails(param.Reference)
+			if err == nil {
+				return transferFromDetails(details), t.markSubmitted(record)
+			}
+			if record.State == ReferenceSubmitted && isNotFound(err) {
+				return nil, &AmbiguousTransferError{
+					Reference: param.Reference,
+					Err:       fmt.Errorf("%w: %v", ErrSubmittedTransferNotFound, err),
+				}
+			}
+			if !isNotFound(err) {
+				return nil, &AmbiguousTransferError{Reference: param.Reference, Err: err}
+			}
+		}
+
+		if attempt == maxAttempts {
+			return nil, &AmbiguousTransferError{Reference: param.Reference, Err: lastErr}
+		}
+
+		result, err := t.Disbursements.SingleTransfer(param)
+		if err == nil {
+			return result, t.markSubmitted(record)
+		}
+		if !ambiguous && isRejected(err) {
+			// The reference is only released while this call holds it; a call that took it over resolves it itself.
+			if _, delErr := t.Store.Delete(param.Reference, record.Owner); delErr != nil {
+				return nil, delErr
+			}
+			return nil, err
+		}
+		ambiguous = true
+		lastErr = err
+	}
+}
+
+func (t *IdempotentTransfers) markSubmitted(record ReferenceRecord) error {
+	if record.State == ReferenceSubmitted {
+		return nil
+	}
+	record.State = ReferenceSubmitted
+	record.Owner, record.ClaimedAt = "", time.Time{}
+	record.UpdatedAt = time.Now().UTC()
+	return t.Store.Update(record)
+}
+
+// release gives up the claim on a reference that is still pending when the call returns, so the next call can resolve
+// it straight away. A claim that was taken over in the meantime is left alone.
+func (t *IdempotentTransfers) release(record ReferenceRecord) {
+	t.Store.Release(record.Reference, record.Owner)
+}
+
+func (t *IdempotentTransfers) claimTimeout() time.Duration {
+	if t.ClaimTimeout <= 0 {
+		return DefaultReferenceClaimTimeout
+	}
+	return t.ClaimTimeout
+}
+
+// newClaimOwner returns a random id for the call claiming a reference.
+func newClaimOwner() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// transferFingerprint identifies a transfer by what it pays, and to whom.
+func transferFingerprint(param params.SingleTransferParam) string {
+	return fmt.Sprintf("%v|%v|%v|%v", param.Amount, param.Currency, param.BankCode, param.AccountNumber)
+}
+
+func transferFromDetails(details *SingleTransferDetailsResponse) *SingleTransferResponse {
+	result := &SingleTransferResponse{apiResponseMeta: details.apiResponseMeta}
+	result.ResponseBody.Amount = details.ResponseBody.Amount
+	result.ResponseBody.Reference = details.ResponseBody.Reference
+	result.ResponseBody.Status = details.ResponseBody.Status
+	result.ResponseBody.DateCreated = details.ResponseBody.DateCreated
+	return result
+}
+
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.HTTPStatus == http.StatusNotFound
+}
+
+// isRejected reports whether Monnify turned the request down, so it is known not to have been processed: a 4xx other
+// than a timeout, or a 200 with requestSuccessful false, which is how Monnify reports most business rejections.
+func isRejected(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.HTTPStatus == http.StatusOK {
+		return true
+	}
+	return apiErr.HTTPStatus >= http.StatusBadRequest && apiErr.HTTPStatus < http.StatusInternalServerError &&
+		apiErr.HTTPStatus != http.StatusRequestTimeout
+}
+
+// NewMemoryReferenceStore returns an empty MemoryReferenceStore.
+func NewMemoryReferenceStore() *MemoryReferenceStore {
+	return &MemoryReferenceStore{records: map[string]ReferenceRecord{}}
+}
+
+func (s *MemoryReferenceStore) Create(record ReferenceRecord) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[record.Reference]; ok {
+		return false, nil
+	}
+	s.records[record.Reference] = record
+	return true, nil
+}
+
+func (s *MemoryReferenceStore) Get(reference string) (ReferenceRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[reference]
+	return record, ok, nil
+}
+
+func (s *MemoryReferenceStore) Update(record ReferenceRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[record.Reference]; !ok {
+		return fmt.Errorf("reference %v is not recorded", record.Reference)
+	}
+	s.records[record.Reference] = record
+	return nil
+}
+
+func (s *MemoryReferenceStore) Claim(record ReferenceRecord, staleBefore time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records[record.Reference]
+	if !ok || existing.State != ReferencePending {
+		return false, nil
+	}
+	if existing.Owner != "" && !existing.ClaimedAt.Before(staleBefore) {
+		return false, nil
+	}
+	existing.Owner, existing.ClaimedAt = record.Owner, record.ClaimedAt
+	existing.UpdatedAt = record.ClaimedAt
+	s.records[record.Reference] = existing
+	return true, nil
+}
+
+func (s *MemoryReferenceStore) Release(reference, owner string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records[reference]
+	if !ok || existing.State != ReferencePending || existing.Owner != owner {
+		return false, nil
+	}
+	existing.Owner, existing.ClaimedAt = "", time.Time{}
+	existing.UpdatedAt = time.Now().UTC()
+	s.records[reference] = existing
+	return true, nil
+}
+
+func (s *MemoryReferenceStore) Delete(reference, owner string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records[reference]
+	if !ok || existing.State != ReferencePending || existing.Owner != owner {
+		return false, nil
+	}
+	delete(s.records, reference)
+	return true, nil
+}
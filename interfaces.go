@@ -0,0 +1,68 @@
+package gomonnify
+
+import (
+	"context"
+	"github.com/jcobhams/gomonnify/params"
+	"time"
+)
+
+//go:generate go run ./internal/fakegen -source interfaces.go -output fakes.go
+
+type (
+	// GeneralAPI is implemented by Monnify.General
+	GeneralAPI interface {
+		Login() (LoginResponse, error)
+		InitializeTransaction(param params.InitializeTransactionParam) (*InitializeTransactionResponse, error)
+		VerifyTransaction(payload *GeneralTransaction, twoStep bool) bool
+		GetTransaction(reference string) (*GeneralTransactionResponse, error)
+		GetBanks() (*BanksResponse, error)
+		GetBanksUseCache() (*BanksResponse, error)
+		InvalidateBankCache()
+	}
+
+	// DisbursementsAPI is implemented by Monnify.Disbursements
+	DisbursementsAPI interface {
+		SingleTransfer(param params.SingleTransferParam) (*SingleTransferResponse, error)
+		BulkTransfer(param params.BulkTransferParam) (*BulkTransferResponse, error)
+		AuthorizeSingleTransfer(reference, authorizationCode string) (*SingleTransferResponse, error)
+		AuthorizeBulkTransfer(reference, authorizationCode string) (*BulkTransferResponse, error)
+		SingleTransferDetails(reference string) (*SingleTransferDetailsResponse, error)
+		BulkTransferDetails(batchReference string) (*BulkTransferDetailsResponse, error)
+		BulkTransferTransactions(batchReference string, pageNo, pageSize int) (*TransferTransactionsResponse, error)
+		SingleTransferTransactions(pageNo, pageSize int) (*TransferTransactionsResponse, error)
+		ValidateAccountNumber(accountNumber, bankCode string) (*ValidAccountNumberResponse, error)
+		WalletBalance(walletId string) (*WalletBalanceResponse, error)
+		WalletStatement(walletId string, from, to time.Time, pageNo, pageSize int) (*WalletStatementResponse, error)
+		ResendOTP(reference string) (*ResendOTPResponse, error)
+		AwaitFinalStatus(ctx context.Context, reference string, policy PollPolicy) (*SingleTransferDetails, error)
+		AwaitBatchFinalStatus(ctx context.Context, batchReference string, policy PollPolicy) (*BulkTransferDetails, error)
+	}
+
+	// ReservedAccountsAPI is implemented by Monnify.ReservedAccounts
+	ReservedAccountsAPI interface {
+		ReserveAccount(param params.ReserveAccountParam) (*ReserveAccountResponse, error)
+		Details(accountReference string) (*ReserveAccountResponse, error)
+		Deallocate(accountNumber string) error
+		Transactions(accountReference string, page, size int) (*ReservedAccountTransactionsResponse, error)
+	}
+
+	// VerificationAPI is implemented by Monnify.Verification
+	VerificationAPI interface {
+		MatchBVNAndAccount(param params.BVNAccountMatchParam) (*BVNAccountMatchResponse, error)
+		VerifyBVN(param params.BVNDetailsParam) (*BVNDetailsResponse, error)
+	}
+
+	// WalletsAPI is implemented by Monnify.Wallets
+	WalletsAPI interface {
+		Create(walletReference, walletName string, customer params.WalletCustomerParam) (*WalletResponse, error)
+		List(customerEmail string, pageNo, pageSize int) (*WalletsResponse, error)
+	}
+)
+
+var (
+	_ GeneralAPI          = &general{}
+	_ DisbursementsAPI    = &disbursements{}
+	_ ReservedAccountsAPI = &reservedAccounts{}
+	_ VerificationAPI     = &verification{}
+	_ WalletsAPI          = &wallets{}
+)
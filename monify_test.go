@@ -1,28 +1,102 @@
 package gomonnify
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/jcobhams/gomonnify/params"
 	"github.com/jcobhams/gomonnify/testhelpers"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
-var client *Monnify
+var (
+	client        *Monnify
+	mockAPIServer *testhelpers.MockServer
+	testConfig    Config
+)
 
 func TestMain(m *testing.M) {
-	mockAPIServer := testhelpers.MockAPIServer()
+	mockAPIServer = testhelpers.NewMockServer()
+
+	testConfig = Config{
+		Environment:         EnvTest,
+		APIKey:              SandBoxAPIKey,
+		SecretKey:           testhelpers.SecretKey,
+		RequestTimeout:      RequestTimeout,
+		DefaultContractCode: DefaultContractCode,
+		BaseURL:             mockAPIServer.URL,
+	}
+	client, _ = New(testConfig)
+
+	os.Exit(m.Run())
+}
+
+// Config Tests
+func TestNew_Config(t *testing.T) {
+	mockAPIServer.Reset()
+	m, err := New(testConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, mockAPIServer.URL, m.General.(*general).APIBaseUrl)
+	assert.Equal(t, testhelpers.SecretKey, m.General.(*general).Config.SecretKey)
 
+	cfg := testConfig
+	cfg.BaseURL = ""
+	_, err = New(cfg)
+	assert.NotNil(t, err)
+
+	m, err = New(DefaultConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, APIBaseUrlSandbox, m.General.(*general).APIBaseUrl)
+
+	cfg = DefaultConfig
+	cfg.BaseURL = mockAPIServer.URL + "/"
+	m, err = New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, mockAPIServer.URL, m.General.(*general).APIBaseUrl)
+	assert.Equal(t, SandBoxSecretKey, m.General.(*general).Config.SecretKey)
+
+	cfg = Config{Environment: EnvLive, APIKey: SandBoxAPIKey, SecretKey: "LIVE_SECRET"}
+	_, err = New(cfg)
+	assert.NotNil(t, err)
+}
+
+func TestNew_TestModeFromEnv(t *testing.T) {
+	mockAPIServer.Reset()
 	os.Setenv("GOMONNIFY_TESTMODE", "ON")
 	os.Setenv("GOMONNIFY_TESTURL", mockAPIServer.URL)
-	client, _ = New(DefaultConfig)
+	defer os.Unsetenv("GOMONNIFY_TESTMODE")
+	defer os.Unsetenv("GOMONNIFY_TESTURL")
 
-	os.Exit(m.Run())
+	m, err := New(DefaultConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, APIBaseUrlSandbox, m.General.(*general).APIBaseUrl)
+
+	cfg := DefaultConfig
+	cfg.TestModeFromEnv = true
+	m, err = New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, EnvTest, m.General.(*general).Config.Environment)
+	assert.Equal(t, mockAPIServer.URL, m.General.(*general).APIBaseUrl)
+	assert.Equal(t, SandBoxSecretKey, m.General.(*general).Config.SecretKey)
 }
 
-//Reserve Account Tests
+// Reserve Account Tests
 func TestReservedAccounts_ReserveAccount(t *testing.T) {
+	mockAPIServer.Reset()
 	opts := params.ReserveAccountParam{
 		AccountReference:      testhelpers.AccountReference,
 		AccountName:           testhelpers.AccountName,
@@ -41,6 +115,7 @@ func TestReservedAccounts_ReserveAccount(t *testing.T) {
 }
 
 func TestReservedAccounts_Details(t *testing.T) {
+	mockAPIServer.Reset()
 	r, err := client.ReservedAccounts.Details(testhelpers.AccountReference)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.AccountName, r.ResponseBody.AccountName)
@@ -50,24 +125,52 @@ func TestReservedAccounts_Details(t *testing.T) {
 }
 
 func TestReservedAccounts_Deallocate(t *testing.T) {
-	err := client.ReservedAccounts.Deallocate(testhelpers.AccountNumber)
+	mockAPIServer.Reset()
+	opts := params.ReserveAccountParam{
+		AccountReference: "TEST_ACCT_REF_DEALLOCATE",
+		AccountName:      testhelpers.AccountName,
+		CurrencyCode:     CurrencyNGN,
+		ContractCode:     testhelpers.ContractCode,
+		CustomerEmail:    testhelpers.CustomerEmail,
+		CustomerName:     testhelpers.CustomerName,
+	}
+	r, err := client.ReservedAccounts.ReserveAccount(opts)
+	assert.Nil(t, err)
+	assert.NotEqual(t, testhelpers.AccountNumber, r.ResponseBody.AccountNumber)
+
+	err = client.ReservedAccounts.Deallocate(r.ResponseBody.AccountNumber)
 	assert.Nil(t, err)
+
+	_, err = client.ReservedAccounts.Details(opts.AccountReference)
+	assert.NotNil(t, err)
+
+	err = client.ReservedAccounts.Deallocate(r.ResponseBody.AccountNumber)
+	assert.NotNil(t, err)
 }
 
 func TestReservedAccounts_Transactions(t *testing.T) {
-	tx, err := client.ReservedAccounts.Transactions(testhelpers.AccountReference, 1, 1)
+	mockAPIServer.Reset()
+	tx, err := client.ReservedAccounts.Transactions(testhelpers.AccountReference, 0, 1)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(tx.ResponseBody.Content))
 	assert.Equal(t, testhelpers.CustomerEmail, tx.ResponseBody.Content[0].CustomerDTO.Email)
 	assert.Equal(t, testhelpers.CustomerName, tx.ResponseBody.Content[0].CustomerDTO.Name)
 	assert.Equal(t, testhelpers.Amount, tx.ResponseBody.Content[0].Amount)
+
+	// Pages are numbered from 0, past the last one they are empty.
+	tx, err = client.ReservedAccounts.Transactions(testhelpers.AccountReference, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(tx.ResponseBody.Content))
+	assert.Equal(t, 1, tx.ResponseBody.TotalPages)
+	assert.True(t, tx.ResponseBody.Last)
 }
 
-//Disbursement Tests
+// Disbursement Tests
 func TestDisbursements_SingleTransfer(t *testing.T) {
+	mockAPIServer.Reset()
 	opts := params.SingleTransferParam{
 		Amount:        testhelpers.Amount,
-		Reference:     testhelpers.TransferReference,
+		Reference:     "TEST_TRF_REF_SINGLE",
 		Narration:     "TEST",
 		BankCode:      testhelpers.BankCode,
 		AccountNumber: testhelpers.AccountNumber,
@@ -77,19 +180,23 @@ func TestDisbursements_SingleTransfer(t *testing.T) {
 	s, err := client.Disbursements.SingleTransfer(opts)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.Amount, s.ResponseBody.Amount)
+
+	_, err = client.Disbursements.SingleTransfer(opts)
+	assert.NotNil(t, err)
 }
 
 func TestDisbursements_BulkTransfer(t *testing.T) {
+	mockAPIServer.Reset()
 	opts := params.BulkTransferParam{
 		Title:                "TEST BATCH",
-		BatchReference:       testhelpers.BatchReference,
+		BatchReference:       "TEST_BCH_REF_BULK",
 		Narration:            "TEST",
 		WalletId:             testhelpers.WalletId,
 		OnValidationFailure:  ValidationFailedContinue,
 		NotificationInterval: NotificationInterval20,
 		TransactionList: []params.SingleTransferParam{{
 			Amount:        testhelpers.Amount,
-			Reference:     testhelpers.TransferReference,
+			Reference:     "TEST_TRF_REF_BULK",
 			Narration:     "TEST",
 			BankCode:      testhelpers.BankCode,
 			AccountNumber: testhelpers.AccountNumber,
@@ -99,35 +206,101 @@ func TestDisbursements_BulkTransfer(t *testing.T) {
 	b, err := client.Disbursements.BulkTransfer(opts)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.Amount, b.ResponseBody.TotalAmount)
-	assert.Equal(t, testhelpers.BatchReference, b.ResponseBody.BatchReference)
+	assert.Equal(t, opts.BatchReference, b.ResponseBody.BatchReference)
+}
+
+func TestDisbursements_BulkTransferItemStatus(t *testing.T) {
+	mockAPIServer.Reset()
+	opts := params.BulkTransferParam{
+		Title:                "TEST BATCH",
+		BatchReference:       "TEST_BCH_REF_ITEMS",
+		Narration:            "TEST",
+		WalletId:             testhelpers.WalletId,
+		OnValidationFailure:  ValidationFailedContinue,
+		NotificationInterval: NotificationInterval20,
+		TransactionList: []params.SingleTransferParam{{
+			Amount:        testhelpers.Amount,
+			Reference:     "TEST_TRF_REF_ITEM_1",
+			Narration:     "TEST",
+			BankCode:      testhelpers.BankCode,
+			AccountNumber: testhelpers.AccountNumber,
+			Currency:      CurrencyNGN,
+		}, {
+			Amount:        params.Naira(100000000),
+			Reference:     "TEST_TRF_REF_ITEM_2",
+			Narration:     "TEST",
+			BankCode:      testhelpers.BankCode,
+			AccountNumber: testhelpers.AccountNumber,
+			Currency:      CurrencyNGN,
+		}},
+	}
+	_, err := client.Disbursements.BulkTransfer(opts)
+	assert.Nil(t, err)
+
+	d, err := client.Disbursements.BulkTransferDetails(opts.BatchReference)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, d.ResponseBody.TotalTransactions)
+	assert.Equal(t, 1, d.ResponseBody.SuccessfulCount)
+	assert.Equal(t, 1, d.ResponseBody.FailedCount)
+
+	tx, err := client.Disbursements.BulkTransferTransactions(opts.BatchReference, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, TransferStatusSuccess, tx.ResponseBody.Content[0].Status)
+	assert.Equal(t, TransferStatusFailed, tx.ResponseBody.Content[1].Status)
+
+	// Pages are cut by pageNo and pageSize.
+	first, err := client.Disbursements.BulkTransferTransactions(opts.BatchReference, 0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(first.ResponseBody.Content))
+	assert.Equal(t, "TEST_TRF_REF_ITEM_1", first.ResponseBody.Content[0].Reference)
+	assert.Equal(t, 2, first.ResponseBody.TotalPages)
+	assert.Equal(t, 2, first.ResponseBody.TotalElements)
+	assert.True(t, first.ResponseBody.First)
+	assert.False(t, first.ResponseBody.Last)
+
+	second, err := client.Disbursements.BulkTransferTransactions(opts.BatchReference, 1, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(second.ResponseBody.Content))
+	assert.Equal(t, "TEST_TRF_REF_ITEM_2", second.ResponseBody.Content[0].Reference)
+	assert.Equal(t, 1, second.ResponseBody.Number)
+	assert.True(t, second.ResponseBody.Last)
+
+	past, err := client.Disbursements.BulkTransferTransactions(opts.BatchReference, 2, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(past.ResponseBody.Content))
 }
 
 func TestDisbursements_AuthorizeSingleTransfer(t *testing.T) {
+	mockAPIServer.Reset()
 	s, err := client.Disbursements.AuthorizeSingleTransfer(testhelpers.TransferReference, testhelpers.ValidOTP)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.Amount, s.ResponseBody.Amount)
 }
 
 func TestDisbursements_AuthorizeBulkTransfer(t *testing.T) {
-	s, err := client.Disbursements.AuthorizeBulkTransfer(testhelpers.TransferReference, testhelpers.ValidOTP)
+	mockAPIServer.Reset()
+	s, err := client.Disbursements.AuthorizeBulkTransfer(testhelpers.BatchReference, testhelpers.ValidOTP)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.Amount, s.ResponseBody.TotalAmount)
 }
 
 func TestDisbursements_SingleTransferDetails(t *testing.T) {
+	mockAPIServer.Reset()
 	d, err := client.Disbursements.SingleTransferDetails(testhelpers.TransferReference)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.Amount, d.ResponseBody.Amount)
 }
 
 func TestDisbursements_BulkTransferDetails(t *testing.T) {
+	mockAPIServer.Reset()
 	d, err := client.Disbursements.BulkTransferDetails(testhelpers.BatchReference)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.Amount, d.ResponseBody.TotalAmount)
 }
 
 func TestDisbursements_BulkTransferTransactions(t *testing.T) {
-	tx, err := client.Disbursements.BulkTransferTransactions(testhelpers.BatchReference, 1, 1)
+	mockAPIServer.Reset()
+	tx, err := client.Disbursements.BulkTransferTransactions(testhelpers.BatchReference, 0, 1)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.BankCode, tx.ResponseBody.Content[0].BankCode)
 	assert.Equal(t, testhelpers.AccountNumber, tx.ResponseBody.Content[0].AccountNumber)
@@ -135,13 +308,15 @@ func TestDisbursements_BulkTransferTransactions(t *testing.T) {
 }
 
 func TestDisbursements_SingleTransferTransactions(t *testing.T) {
-	tx, err := client.Disbursements.SingleTransferTransactions(1, 1)
+	mockAPIServer.Reset()
+	tx, err := client.Disbursements.SingleTransferTransactions(0, 1)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.BankCode, tx.ResponseBody.Content[0].BankCode)
 	assert.Equal(t, testhelpers.Amount, tx.ResponseBody.Content[0].Amount)
 }
 
 func TestDisbursements_ValidateAccountNumber(t *testing.T) {
+	mockAPIServer.Reset()
 	d, err := client.Disbursements.ValidateAccountNumber(testhelpers.AccountNumber, testhelpers.BankCode)
 	assert.Nil(t, err)
 	assert.Equal(t, testhelpers.AccountNumber, d.ResponseBody.AccountNumber)
@@ -150,46 +325,1694 @@ func TestDisbursements_ValidateAccountNumber(t *testing.T) {
 }
 
 func TestDisbursements_WalletBalance(t *testing.T) {
+	mockAPIServer.Reset()
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, testhelpers.Amount+testhelpers.TransferFee))
+	available, ledger, _ := mockAPIServer.WalletBalance(testhelpers.WalletId)
 	w, err := client.Disbursements.WalletBalance(testhelpers.WalletId)
 	assert.Nil(t, err)
-	assert.Equal(t, testhelpers.AvailableBalance, w.ResponseBody.AvailableBalance)
-	assert.Equal(t, testhelpers.LedgerBalance, w.ResponseBody.LedgerBalance)
+	assert.Equal(t, available, w.ResponseBody.AvailableBalance)
+	assert.Equal(t, ledger, w.ResponseBody.LedgerBalance)
+
+	opts := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_BALANCE",
+		Narration:     "TEST",
+		BankCode:      testhelpers.BankCode,
+		AccountNumber: testhelpers.AccountNumber,
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	_, err = client.Disbursements.SingleTransfer(opts)
+	assert.Nil(t, err)
+
+	after, err := client.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.Nil(t, err)
+	assert.Equal(t, testhelpers.Amount+testhelpers.TransferFee, w.ResponseBody.AvailableBalance-after.ResponseBody.AvailableBalance)
+	assert.Equal(t, testhelpers.Amount+testhelpers.TransferFee, w.ResponseBody.LedgerBalance-after.ResponseBody.LedgerBalance)
+
+	_, err = client.Disbursements.WalletBalance("UNKNOWN_WLT_ID")
+	assert.NotNil(t, err)
+}
+
+func TestDisbursements_WalletStatement(t *testing.T) {
+	mockAPIServer.Reset()
+	from := time.Now()
+	opts := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_STATEMENT",
+		Narration:     "TEST",
+		BankCode:      testhelpers.BankCode,
+		AccountNumber: testhelpers.AccountNumber,
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, testhelpers.Amount+testhelpers.TransferFee))
+	_, err := client.Disbursements.SingleTransfer(opts)
+	assert.Nil(t, err)
+	to := time.Now().Add(time.Minute)
+
+	st, err := client.Disbursements.WalletStatement(testhelpers.WalletId, from, to, 0, 10)
+	assert.Nil(t, err)
+
+	var entries []WalletStatementEntry
+	credit := -1
+	for i, e := range st.ResponseBody.Content {
+		if strings.HasPrefix(e.WalletTransactionReference, opts.Reference) {
+			if len(entries) == 0 {
+				credit = i - 1
+			}
+			entries = append(entries, e)
+		}
+	}
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, WalletTransactionDebit, entries[0].TransactionType)
+	assert.Equal(t, testhelpers.Amount, entries[0].Amount)
+	assert.Equal(t, testhelpers.Amount, entries[0].BalanceBefore-entries[0].BalanceAfter)
+	assert.Equal(t, WalletTransactionFee, entries[1].TransactionType)
+	assert.Equal(t, testhelpers.TransferFee, entries[1].Fee)
+	assert.Equal(t, entries[0].BalanceAfter, entries[1].BalanceBefore)
+
+	// The statement is in posting order, so the top up made for the transfer comes right before it. Earlier
+	// activity on the wallet may fall in the same millisecond as from, and is not counted on.
+	if assert.True(t, credit >= 0) {
+		topUp := st.ResponseBody.Content[credit]
+		assert.Equal(t, WalletTransactionCredit, topUp.TransactionType)
+		assert.Equal(t, testhelpers.Amount+testhelpers.TransferFee, topUp.Amount)
+		assert.Equal(t, topUp.BalanceAfter, entries[0].BalanceBefore)
+	}
+
+	st, err = client.Disbursements.WalletStatement(testhelpers.WalletId, to, time.Time{}, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(st.ResponseBody.Content))
+
+	_, err = client.Disbursements.WalletStatement(testhelpers.WalletId, to, from, 0, 10)
+	assert.NotNil(t, err)
+
+	_, err = client.Disbursements.WalletStatement("", from, to, 0, 10)
+	assert.NotNil(t, err)
 }
 
 func TestDisbursements_ResendOTP(t *testing.T) {
+	mockAPIServer.Reset()
 	r, err := client.Disbursements.ResendOTP(testhelpers.TransferReference)
 	assert.Nil(t, err)
 	assert.NotNil(t, r)
 }
 
+func TestDisbursements_AwaitFinalStatus(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.SetTransferStatus(testhelpers.TransferReference, testhelpers.TransferStatusSuccess)
+	defer mockAPIServer.ResetFaults()
+
+	policy := PollPolicy{InitialInterval: time.Millisecond, MaxInterval: 4 * time.Millisecond, Multiplier: 2}
+
+	assert.True(t, mockAPIServer.SetTransferStatus(testhelpers.TransferReference, "PENDING"))
+	mockAPIServer.FailNext("/v1/disbursements/single/summary", 1, http.StatusServiceUnavailable, "D99", "Service unavailable")
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		mockAPIServer.SetTransferStatus(testhelpers.TransferReference, "REVERSED")
+	}()
+	d, err := client.Disbursements.AwaitFinalStatus(context.Background(), testhelpers.TransferReference, policy)
+	assert.Nil(t, err)
+	assert.Equal(t, TransferStatusReversed, d.Status)
+	assert.Equal(t, testhelpers.TransferReference, d.Reference)
+
+	assert.True(t, mockAPIServer.SetTransferStatus(testhelpers.TransferReference, "PENDING_AUTHORIZATION"))
+	policy.MaxAttempts = 3
+	d, err = client.Disbursements.AwaitFinalStatus(context.Background(), testhelpers.TransferReference, policy)
+	assert.Equal(t, ErrPollAttemptsExhausted, err)
+	assert.Equal(t, TransferStatusPendingAuthorization, d.Status)
+
+	policy.MaxAttempts = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Disbursements.AwaitFinalStatus(ctx, testhelpers.TransferReference, policy)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	_, err = client.Disbursements.AwaitFinalStatus(context.Background(), "TEST_UNKNOWN_REF", policy)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.HTTPStatus)
+}
+
+func TestDisbursements_AwaitBatchFinalStatus(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.SetBatchStatus(testhelpers.BatchReference, testhelpers.BatchStatusCompleted)
+
+	assert.True(t, mockAPIServer.SetBatchStatus(testhelpers.BatchReference, "AWAITING_PROCESSING"))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		mockAPIServer.SetBatchStatus(testhelpers.BatchReference, testhelpers.BatchStatusCompleted)
+	}()
+	policy := PollPolicy{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+	b, err := client.Disbursements.AwaitBatchFinalStatus(context.Background(), testhelpers.BatchReference, policy)
+	assert.Nil(t, err)
+	assert.Equal(t, BatchStatusCompleted, b.BatchStatus)
+	assert.Equal(t, testhelpers.BatchReference, b.BatchReference)
+}
+
+func TestIdempotentTransfers_SingleTransfer(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetFaults()
+	defer mockAPIServer.ResetRequests()
+
+	store := NewMemoryReferenceStore()
+	transfers := NewIdempotentTransfers(client.Disbursements, store)
+	opts := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_IDEMPOTENT",
+		Narration:     "TEST",
+		BankCode:      testhelpers.BankCode,
+		AccountNumber: testhelpers.AccountNumber,
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, 3*(testhelpers.Amount+testhelpers.TransferFee)))
+
+	// Monnify accepts the transfer but the response is lost, so it is found instead of being sent again.
+	mockAPIServer.ResetRequests()
+	mockAPIServer.InjectFault("/v1/disbursements/single", 1, testhelpers.Fault{DropResponse: true})
+	r, err := transfers.SingleTransfer(opts)
+	assert.Nil(t, err)
+	assert.Equal(t, opts.Reference, r.ResponseBody.Reference)
+	assert.Equal(t, TransferStatusSuccess, r.ResponseBody.Status)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single", 1))
+	record, found, _ := store.Get(opts.Reference)
+	assert.True(t, found)
+	assert.Equal(t, ReferenceSubmitted, record.State)
+
+	// Sending it again returns the transfer Monnify has.
+	mockAPIServer.ResetRequests()
+	r, err = transfers.SingleTransfer(opts)
+	assert.Nil(t, err)
+	assert.Equal(t, opts.Reference, r.ResponseBody.Reference)
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single"))
+
+	other := opts
+	other.Amount = testhelpers.Amount + 1
+	_, err = transfers.SingleTransfer(other)
+	assert.Equal(t, ErrReferenceConflict, err)
+
+	// The connection drops before Monnify gets the transfer, so it is sent again.
+	opts.Reference = "TEST_TRF_REF_IDEMPOTENT_RETRY"
+	mockAPIServer.ResetRequests()
+	mockAPIServer.InjectFault("/v1/disbursements/single", 1, testhelpers.Fault{DropConnection: true})
+	r, err = transfers.SingleTransfer(opts)
+	assert.Nil(t, err)
+	assert.Equal(t, opts.Reference, r.ResponseBody.Reference)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single", 2))
+
+	// Monnify cannot be reached to resolve the transfer, so it stays pending.
+	opts.Reference = "TEST_TRF_REF_IDEMPOTENT_UNKNOWN"
+	mockAPIServer.InjectFault("/v1/disbursements/single", 1, testhelpers.Fault{DropConnection: true})
+	mockAPIServer.FailNext("/v1/disbursements/single/summary", 1, http.StatusServiceUnavailable, "D99", "Service unavailable")
+	_, err = transfers.SingleTransfer(opts)
+	var ambiguousErr *AmbiguousTransferError
+	assert.True(t, errors.As(err, &ambiguousErr))
+	assert.Equal(t, opts.Reference, ambiguousErr.Reference)
+	record, _, _ = store.Get(opts.Reference)
+	assert.Equal(t, ReferencePending, record.State)
+
+	r, err = transfers.SingleTransfer(opts)
+	assert.Nil(t, err)
+	assert.Equal(t, opts.Reference, r.ResponseBody.Reference)
+
+	// A rejected transfer releases its reference.
+	opts.Reference = "TEST_TRF_REF_IDEMPOTENT_REJECTED"
+	opts.Amount = params.Naira(100000000)
+	_, err = transfers.SingleTransfer(opts)
+	assert.NotNil(t, err)
+	_, found, _ = store.Get(opts.Reference)
+	assert.False(t, found)
+
+	// So does a rejection sent with HTTP 200 and requestSuccessful false, without querying the details.
+	opts.Reference = "TEST_TRF_REF_IDEMPOTENT_REJECTED_200"
+	mockAPIServer.ResetRequests()
+	mockAPIServer.FailNext("/v1/disbursements/single", 10, http.StatusOK, "D01", "Insufficient balance")
+	_, err = transfers.SingleTransfer(opts)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusOK, apiErr.HTTPStatus)
+	assert.Equal(t, "D01", apiErr.ResponseCode)
+	assert.False(t, errors.As(err, &ambiguousErr))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single", 1))
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single/summary"))
+	_, found, _ = store.Get(opts.Reference)
+	assert.False(t, found)
+}
+
+func TestIdempotentTransfers_Claims(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetFaults()
+	defer mockAPIServer.ResetRequests()
+
+	store := NewMemoryReferenceStore()
+	transfers := NewIdempotentTransfers(client.Disbursements, store)
+	opts := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_CLAIM_FRESH",
+		Narration:     "TEST",
+		BankCode:      testhelpers.BankCode,
+		AccountNumber: testhelpers.AccountNumber,
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, 2*(testhelpers.Amount+testhelpers.TransferFee)))
+
+	// A reference freshly claimed by another call is left to it.
+	now := time.Now().UTC()
+	claim := ReferenceRecord{
+		Reference:   opts.Reference,
+		Fingerprint: transferFingerprint(opts),
+		State:       ReferencePending,
+		Owner:       "other",
+		ClaimedAt:   now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	created, _ := store.Create(claim)
+	assert.True(t, created)
+	mockAPIServer.ResetRequests()
+	_, err := transfers.SingleTransfer(opts)
+	var ambiguousErr *AmbiguousTransferError
+	assert.True(t, errors.As(err, &ambiguousErr))
+	assert.True(t, errors.Is(err, ErrReferenceInFlight))
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single"))
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single/summary"))
+	record, _, _ := store.Get(opts.Reference)
+	assert.Equal(t, "other", record.Owner)
+
+	// An abandoned claim is taken over and resolved.
+	claim.ClaimedAt = now.Add(-2 * DefaultReferenceClaimTimeout)
+	assert.Nil(t, store.Update(claim))
+	r, err := transfers.SingleTransfer(opts)
+	assert.Nil(t, err)
+	assert.Equal(t, opts.Reference, r.ResponseBody.Reference)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single/summary", 1))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single", 1))
+	record, _, _ = store.Get(opts.Reference)
+	assert.Equal(t, ReferenceSubmitted, record.State)
+	assert.Equal(t, "", record.Owner)
+
+	// A second call arriving while the first one is sending the transfer does not send it again.
+	opts.Reference = "TEST_TRF_REF_CLAIM_CONCURRENT"
+	mockAPIServer.ResetRequests()
+	mockAPIServer.SetLatency("/v1/disbursements/single", 100*time.Millisecond)
+	defer mockAPIServer.SetLatency("/v1/disbursements/single", 0)
+	done := make(chan error)
+	go func() {
+		_, err := transfers.SingleTransfer(opts)
+		done <- err
+	}()
+	for {
+		if _, found, _ := store.Get(opts.Reference); found {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	_, err = transfers.SingleTransfer(opts)
+	assert.True(t, errors.Is(err, ErrReferenceInFlight))
+	assert.Nil(t, <-done)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single", 1))
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single/summary"))
+
+	// A claim taken over while the transfer is sent is neither deleted by the rejection nor released.
+	opts.Reference = "TEST_TRF_REF_CLAIM_TAKEN_OVER"
+	mockAPIServer.FailNext("/v1/disbursements/single", 1, http.StatusBadRequest, "D01", "Insufficient balance")
+	go func() {
+		_, err := transfers.SingleTransfer(opts)
+		done <- err
+	}()
+	for {
+		if _, found, _ := store.Get(opts.Reference); found {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	takeOver := ReferenceRecord{Reference: opts.Reference, Owner: "other", ClaimedAt: time.Now().UTC()}
+	claimed, err := store.Claim(takeOver, time.Now().Add(time.Hour))
+	assert.Nil(t, err)
+	assert.True(t, claimed)
+	err = <-done
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	record, found, _ := store.Get(opts.Reference)
+	assert.True(t, found)
+	assert.Equal(t, "other", record.Owner)
+
+	// Only the owner of a claim can release or delete it.
+	released, err := store.Release(opts.Reference, "someone")
+	assert.Nil(t, err)
+	assert.False(t, released)
+	deleted, err := store.Delete(opts.Reference, "someone")
+	assert.Nil(t, err)
+	assert.False(t, deleted)
+	released, _ = store.Release(opts.Reference, "other")
+	assert.True(t, released)
+	deleted, _ = store.Delete(opts.Reference, "other")
+	assert.False(t, deleted)
+	claimed, _ = store.Claim(takeOver, time.Now())
+	assert.True(t, claimed)
+	deleted, _ = store.Delete(opts.Reference, "other")
+	assert.True(t, deleted)
+}
+
+func TestIdempotentTransfers_SubmittedNotFound(t *testing.T) {
+	mockAPIServer.Reset()
+
+	store := NewMemoryReferenceStore()
+	transfers := NewIdempotentTransfers(client.Disbursements, store)
+	opts := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_SUBMITTED_MISSING",
+		Narration:     "TEST",
+		BankCode:      testhelpers.BankCode,
+		AccountNumber: testhelpers.AccountNumber,
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	now := time.Now().UTC()
+	created, _ := store.Create(ReferenceRecord{
+		Reference:   opts.Reference,
+		Fingerprint: transferFingerprint(opts),
+		State:       ReferenceSubmitted,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	assert.True(t, created)
+
+	// Monnify accepted the reference before, so a missing transfer is never sent again.
+	_, err := transfers.SingleTransfer(opts)
+	var ambiguousErr *AmbiguousTransferError
+	assert.True(t, errors.As(err, &ambiguousErr))
+	assert.True(t, errors.Is(err, ErrSubmittedTransferNotFound))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single/summary", 1))
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single"))
+	record, _, _ := store.Get(opts.Reference)
+	assert.Equal(t, ReferenceSubmitted, record.State)
+}
+
+func TestBulkTransferBuilder_Send(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetFaults()
+	defer mockAPIServer.ResetRequests()
+
+	batch := params.BulkTransferParam{
+		Title:                "Payroll",
+		BatchReference:       "TEST_BCH_REF_BUILDER",
+		Narration:            "Salary",
+		WalletId:             testhelpers.WalletId,
+		OnValidationFailure:  ValidationFailedContinue,
+		NotificationInterval: NotificationInterval10,
+	}
+	_, err := NewBulkTransferBuilder(client.Disbursements, params.BulkTransferParam{Title: "Payroll"}).Send(context.Background())
+	var validationErrs ValidationErrors
+	assert.True(t, errors.As(err, &validationErrs))
+	_, found := validationErrs.Field("transactionList")
+	assert.False(t, found)
+
+	// The references derived for every batch must be valid too.
+	long := batch
+	long.BatchReference = strings.Repeat("R", params.MaxReferenceLength-1)
+	builder := NewBulkTransferBuilder(client.Disbursements, long)
+	builder.MaxBatchSize = 1
+	builder.Add(params.SingleTransferParam{Reference: "TEST_TRF_REF_BUILDER_LONG_1"}, params.SingleTransferParam{Reference: "TEST_TRF_REF_BUILDER_LONG_2"})
+	_, err = builder.Send(context.Background())
+	assert.True(t, errors.As(err, &validationErrs))
+	_, found = validationErrs.Field("batchReference")
+	assert.True(t, found)
+
+	builder = NewBulkTransferBuilder(client.Disbursements, batch)
+	builder.MaxBatchSize = 2
+	builder.Workers = 3
+	for i := 1; i <= 5; i++ {
+		builder.Add(params.SingleTransferParam{
+			Amount:        testhelpers.Amount,
+			Reference:     fmt.Sprintf("TEST_TRF_REF_BUILDER_%d", i),
+			Narration:     "Salary",
+			BankCode:      testhelpers.BankCode,
+			AccountNumber: testhelpers.AccountNumber,
+			Currency:      CurrencyNGN,
+		})
+	}
+	duplicate := builder.items[0]
+	invalid := builder.items[1]
+	invalid.Reference, invalid.AccountNumber = "TEST_TRF_REF_BUILDER_INVALID", "123"
+	builder.Add(duplicate, invalid)
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, 5*(testhelpers.Amount+testhelpers.TransferFee)))
+
+	mockAPIServer.ResetRequests()
+	mockAPIServer.FailNext("/v1/disbursements/batch", 1, http.StatusServiceUnavailable, "D99", "Service unavailable")
+	report, err := builder.Send(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/account/validate", 5))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/batch", 3))
+
+	assert.Len(t, report.Batches, 3)
+	assert.Equal(t, "TEST_BCH_REF_BUILDER-1", report.Batches[0].BatchReference)
+	assert.NotNil(t, report.Batches[0].Err)
+	assert.Equal(t, "TEST_BCH_REF_BUILDER-3", report.Batches[2].BatchReference)
+	assert.Equal(t, 1, report.Batches[2].Transfers)
+
+	assert.Empty(t, report.Failed)
+	assert.Len(t, report.Ambiguous, 2)
+	assert.Equal(t, "TEST_TRF_REF_BUILDER_1", report.Ambiguous[0].Transfer.Reference)
+	assert.Len(t, report.Submitted, 3)
+	assert.Equal(t, "TEST_TRF_REF_BUILDER_3", report.Submitted[0].Transfer.Reference)
+	assert.Equal(t, "TEST_BCH_REF_BUILDER-2", report.Submitted[0].BatchReference)
+	assert.Len(t, report.Rejected, 2)
+	assert.Equal(t, duplicate.Reference, report.Rejected[0].Transfer.Reference)
+	assert.Equal(t, invalid.Reference, report.Rejected[1].Transfer.Reference)
+
+	mockAPIServer.ResetRequests()
+	builder = NewBulkTransferBuilder(client.Disbursements, batch)
+	builder.Add(duplicate)
+	mockAPIServer.FailNext("/v1/disbursements/account/validate", 1, http.StatusBadRequest, "99", "Account not found")
+	report, err = builder.Send(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, report.Rejected, 1)
+	assert.Contains(t, report.Rejected[0].Err.Error(), "Account not found")
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/batch"))
+
+	// Monnify also rejects account numbers with HTTP 200.
+	mockAPIServer.FailNext("/v1/disbursements/account/validate", 1, http.StatusOK, "99", "Account not found")
+	report, err = builder.Send(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, report.Rejected, 1)
+	assert.Empty(t, report.Failed)
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/batch"))
+
+	// An invalid transfer does not hold its reference, and a rejected batch is definite.
+	valid := duplicate
+	valid.Reference = "TEST_TRF_REF_BUILDER_REUSED"
+	invalid = valid
+	invalid.Amount = 0
+	builder = NewBulkTransferBuilder(client.Disbursements, batch)
+	builder.SkipAccountValidation = true
+	builder.Add(invalid, valid)
+	mockAPIServer.ResetRequests()
+	mockAPIServer.FailNext("/v1/disbursements/batch", 1, http.StatusBadRequest, "D01", "Insufficient balance")
+	report, err = builder.Send(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, report.Rejected, 1)
+	assert.Len(t, report.Failed, 1)
+	assert.Equal(t, valid.Reference, report.Failed[0].Transfer.Reference)
+	assert.Empty(t, report.Ambiguous)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/batch", 1))
+}
+
+func TestTransferWithAuthorization(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetRequests()
+	defer mockAPIServer.RequireAuthorization(false)
+
+	policy := AuthorizationPolicy{MaxAttempts: 2, ResendInterval: time.Millisecond}
+	transfer := func(reference string) params.SingleTransferParam {
+		return params.SingleTransferParam{
+			Amount:        testhelpers.Amount,
+			Reference:     reference,
+			Narration:     "TEST",
+			BankCode:      testhelpers.BankCode,
+			AccountNumber: testhelpers.AccountNumber,
+			Currency:      CurrencyNGN,
+			WalletId:      testhelpers.WalletId,
+		}
+	}
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, 5*(testhelpers.Amount+testhelpers.TransferFee)))
+
+	// Transfers that do not need authorization are returned straight away.
+	mockAPIServer.ResetRequests()
+	r, err := TransferWithAuthorization(context.Background(), client.Disbursements, transfer("TEST_TRF_REF_OTP_NONE"), OTPChannel(nil), policy)
+	assert.Nil(t, err)
+	assert.Equal(t, TransferStatusSuccess, r.ResponseBody.Status)
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single/validate-otp"))
+
+	mockAPIServer.RequireAuthorization(true)
+
+	// A rejected OTP is followed by a resend and a new attempt.
+	mockAPIServer.ResetRequests()
+	otps := make(chan string, 2)
+	otps <- "000000"
+	otps <- testhelpers.ValidOTP
+	r, err = TransferWithAuthorization(context.Background(), client.Disbursements, transfer("TEST_TRF_REF_OTP"), OTPChannel(otps), policy)
+	assert.Nil(t, err)
+	assert.Equal(t, TransferStatusSuccess, r.ResponseBody.Status)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single/validate-otp", 2))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single/resend-otp", 1))
+
+	// OTPs rejected with HTTP 200 are retried too.
+	mockAPIServer.ResetRequests()
+	otps <- testhelpers.ValidOTP
+	otps <- testhelpers.ValidOTP
+	mockAPIServer.FailNext("/v1/disbursements/single/validate-otp", 1, http.StatusOK, "99", "Invalid authorization code")
+	r, err = TransferWithAuthorization(context.Background(), client.Disbursements, transfer("TEST_TRF_REF_OTP_REJECTED_200"), OTPChannel(otps), policy)
+	assert.Nil(t, err)
+	assert.Equal(t, TransferStatusSuccess, r.ResponseBody.Status)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single/validate-otp", 2))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single/resend-otp", 1))
+
+	// The attempts are bounded and the transfer is left pending.
+	mockAPIServer.ResetRequests()
+	var requests []OTPRequest
+	provider := OTPProviderFunc(func(ctx context.Context, request OTPRequest) (string, error) {
+		requests = append(requests, request)
+		return "000000", nil
+	})
+	r, err = TransferWithAuthorization(context.Background(), client.Disbursements, transfer("TEST_TRF_REF_OTP_WRONG"), provider, policy)
+	assert.True(t, errors.Is(err, ErrOTPAttemptsExhausted))
+	assert.Equal(t, TransferStatusPendingAuthorization, r.ResponseBody.Status)
+	assert.Equal(t, []OTPRequest{
+		{Reference: "TEST_TRF_REF_OTP_WRONG", Attempt: 1},
+		{Reference: "TEST_TRF_REF_OTP_WRONG", Attempt: 2, Resent: true},
+	}, requests)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single/validate-otp", 2))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single/resend-otp", 1))
+
+	// Waiting for an OTP stops with the context.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	r, err = TransferWithAuthorization(ctx, client.Disbursements, transfer("TEST_TRF_REF_OTP_TIMEOUT"), OTPChannel(make(chan string)), policy)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, TransferStatusPendingAuthorization, r.ResponseBody.Status)
+
+	// Bulk transfers can be authorized over HTTP with an OTPPrompt.
+	prompt := NewOTPPrompt()
+	promptServer := httptest.NewServer(prompt)
+	defer promptServer.Close()
+	go func() {
+		for {
+			resp, err := http.Get(promptServer.URL)
+			if err != nil {
+				return
+			}
+			var pending []OTPRequest
+			json.NewDecoder(resp.Body).Decode(&pending)
+			resp.Body.Close()
+			if len(pending) > 0 {
+				body := fmt.Sprintf(`{"reference": %q, "otp": %q}`, pending[0].Reference, testhelpers.ValidOTP)
+				if resp, err := http.Post(promptServer.URL, "application/json", strings.NewReader(body)); err == nil {
+					resp.Body.Close()
+				}
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	batch := params.BulkTransferParam{
+		Title:                "TEST BATCH",
+		BatchReference:       "TEST_BCH_REF_OTP",
+		Narration:            "TEST",
+		WalletId:             testhelpers.WalletId,
+		OnValidationFailure:  ValidationFailedContinue,
+		NotificationInterval: NotificationInterval20,
+		TransactionList:      []params.SingleTransferParam{transfer("TEST_TRF_REF_OTP_BATCH")},
+	}
+	b, err := BulkTransferWithAuthorization(context.Background(), client.Disbursements, batch, prompt, policy)
+	assert.Nil(t, err)
+	assert.Equal(t, BatchStatusCompleted, b.ResponseBody.BatchStatus)
+	assert.Empty(t, prompt.Pending())
+	assert.False(t, prompt.Submit("TEST_BCH_REF_OTP", testhelpers.ValidOTP))
+
+	// The OTP of a batch cannot be resent, so the provider is asked again for the one already sent.
+	mockAPIServer.ResetRequests()
+	requests = nil
+	provider = OTPProviderFunc(func(ctx context.Context, request OTPRequest) (string, error) {
+		requests = append(requests, request)
+		if len(requests) == 1 {
+			return "000000", nil
+		}
+		return testhelpers.ValidOTP, nil
+	})
+	batch.BatchReference = "TEST_BCH_REF_OTP_RETRY"
+	batch.TransactionList = []params.SingleTransferParam{transfer("TEST_TRF_REF_OTP_BATCH_RETRY")}
+	b, err = BulkTransferWithAuthorization(context.Background(), client.Disbursements, batch, provider, policy)
+	assert.Nil(t, err)
+	assert.Equal(t, BatchStatusCompleted, b.ResponseBody.BatchStatus)
+	assert.Equal(t, []OTPRequest{
+		{Reference: "TEST_BCH_REF_OTP_RETRY", Batch: true, Attempt: 1},
+		{Reference: "TEST_BCH_REF_OTP_RETRY", Batch: true, Attempt: 2},
+	}, requests)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/batch/validate-otp", 2))
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single/resend-otp"))
+
+	// A batch whose OTP could not be emailed is left pending, as the OTP cannot be resent.
+	fake := &FakeDisbursements{
+		BulkTransferFunc: func(param params.BulkTransferParam) (*BulkTransferResponse, error) {
+			res := &BulkTransferResponse{}
+			res.ResponseBody.BatchReference = param.BatchReference
+			res.ResponseBody.BatchStatus = BatchStatusOTPEmailDispatchFailed
+			return res, nil
+		},
+	}
+	b, err = BulkTransferWithAuthorization(context.Background(), fake, batch, provider, policy)
+	assert.Equal(t, ErrBatchOTPNotSent, err)
+	assert.Equal(t, BatchStatusOTPEmailDispatchFailed, b.ResponseBody.BatchStatus)
+}
+
+func TestApprovalGate(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetRequests()
+
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	var events []AuditEvent
+	auditErr := error(nil)
+	audit := AuditSinkFunc(func(event AuditEvent) error {
+		if auditErr != nil {
+			return auditErr
+		}
+		events = append(events, event)
+		return nil
+	})
+	actions := func() []string {
+		var a []string
+		for _, e := range events {
+			a = append(a, fmt.Sprintf("%v %v %v", e.Action, e.ActorID, e.Status))
+		}
+		events = nil
+		return a
+	}
+
+	store := NewMemoryIntentStore()
+	gate := NewApprovalGate(client.Disbursements, store, audit, 2)
+	gate.TTL = time.Hour
+	gate.Clock = func() time.Time { return now }
+
+	transfer := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_APPROVAL",
+		Narration:     "TEST",
+		BankCode:      testhelpers.BankCode,
+		AccountNumber: testhelpers.AccountNumber,
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, testhelpers.Amount+testhelpers.TransferFee))
+	mockAPIServer.ResetRequests()
+
+	intent, err := gate.ProposeSingleTransfer("maker", transfer)
+	assert.Nil(t, err)
+	assert.Equal(t, IntentPending, intent.Status)
+	assert.Equal(t, now.Add(time.Hour), intent.ExpiresAt)
+	_, err = gate.ProposeSingleTransfer("maker", transfer)
+	assert.Equal(t, ErrIntentExists, err)
+	_, err = gate.ProposeSingleTransfer("maker", params.SingleTransferParam{Reference: "TEST_TRF_REF_APPROVAL_INVALID"})
+	assert.NotNil(t, err)
+
+	// The maker cannot approve and every approver counts once.
+	_, err = gate.Approve(intent.ID, "maker")
+	assert.Equal(t, ErrSelfApproval, err)
+	r, err := gate.Approve(intent.ID, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, IntentPending, r.Intent.Status)
+	assert.Nil(t, r.SingleTransfer)
+	_, err = gate.Approve(intent.ID, "alice")
+	assert.Equal(t, ErrDuplicateApproval, err)
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/single"))
+
+	// The transfer is sent with the last approval.
+	r, err = gate.Approve(intent.ID, "bob")
+	assert.Nil(t, err)
+	assert.Equal(t, IntentSubmitted, r.Intent.Status)
+	assert.Equal(t, TransferStatusSuccess, r.SingleTransfer.ResponseBody.Status)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single", 1))
+	_, err = gate.Approve(intent.ID, "carol")
+	assert.Equal(t, ErrIntentNotPending, err)
+	assert.Equal(t, []string{
+		"PROPOSED maker PENDING",
+		"DENIED maker PENDING",
+		"APPROVED alice PENDING",
+		"DENIED alice PENDING",
+		"APPROVED bob APPROVED",
+		"SUBMITTED  SUBMITTED",
+		"DENIED carol SUBMITTED",
+	}, actions())
+
+	// A rejection turns a bulk transfer down.
+	batch := params.BulkTransferParam{
+		Title:                "TEST BATCH",
+		BatchReference:       "TEST_BCH_REF_APPROVAL",
+		Narration:            "TEST",
+		WalletId:             testhelpers.WalletId,
+		OnValidationFailure:  ValidationFailedContinue,
+		NotificationInterval: NotificationInterval20,
+		TransactionList:      []params.SingleTransferParam{transfer},
+	}
+	intent, err = gate.ProposeBulkTransfer("maker", batch)
+	assert.Nil(t, err)
+	rejected, err := gate.Reject(intent.ID, "alice", "wrong amount")
+	assert.Nil(t, err)
+	assert.Equal(t, IntentRejected, rejected.Status)
+	_, err = gate.Approve(intent.ID, "bob")
+	assert.Equal(t, ErrIntentNotPending, err)
+	assert.Equal(t, []string{"PROPOSED maker PENDING", "REJECTED alice REJECTED", "DENIED bob REJECTED"}, actions())
+
+	// Intents that are not approved in time expire.
+	transfer.Reference = "TEST_TRF_REF_APPROVAL_EXPIRED"
+	intent, err = gate.ProposeSingleTransfer("maker", transfer)
+	assert.Nil(t, err)
+	pending, err := gate.Pending()
+	assert.Nil(t, err)
+	assert.Len(t, pending, 1)
+	now = now.Add(time.Hour)
+	pending, err = gate.Pending()
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+	_, err = gate.Approve(intent.ID, "alice")
+	assert.Equal(t, ErrIntentExpired, err)
+	intent, err = gate.Intent(intent.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, IntentExpired, intent.Status)
+	assert.Equal(t, []string{"PROPOSED maker PENDING", "EXPIRED  EXPIRED", "DENIED alice EXPIRED"}, actions())
+
+	// Decisions that cannot be audited are not taken.
+	transfer.Reference = "TEST_TRF_REF_APPROVAL_AUDIT"
+	intent, err = gate.ProposeSingleTransfer("maker", transfer)
+	assert.Nil(t, err)
+	auditErr = errors.New("audit log unavailable")
+	_, err = gate.Approve(intent.ID, "alice")
+	assert.True(t, errors.Is(err, auditErr))
+	intent, err = gate.Intent(intent.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, intent.Approvals)
+
+	// Concurrent changes are detected by the store.
+	stale := *intent
+	stale.Version++
+	assert.Nil(t, store.Update(stale))
+	assert.Equal(t, ErrIntentConflict, store.Update(stale))
+
+	_, err = gate.Approve("TEST_TRF_REF_APPROVAL_UNKNOWN", "alice")
+	assert.Equal(t, ErrIntentNotFound, err)
+
+	// The proposed transfer cannot be changed through the params or the returned intent.
+	var sentSingle params.SingleTransferParam
+	var sentBulk params.BulkTransferParam
+	fake := &FakeDisbursements{
+		SingleTransferFunc: func(param params.SingleTransferParam) (*SingleTransferResponse, error) {
+			sentSingle = param
+			return &SingleTransferResponse{}, nil
+		},
+		BulkTransferFunc: func(param params.BulkTransferParam) (*BulkTransferResponse, error) {
+			sentBulk = param
+			return &BulkTransferResponse{}, nil
+		},
+	}
+	fakeGate := NewApprovalGate(fake, NewMemoryIntentStore(), AuditSinkFunc(func(AuditEvent) error { return nil }), 1)
+	transfer.Reference = "TEST_TRF_REF_APPROVAL_TAMPERED"
+	intent, err = fakeGate.ProposeSingleTransfer("maker", transfer)
+	assert.Nil(t, err)
+	intent.Single.Amount, intent.Single.AccountNumber = transfer.Amount*100, "9999999999"
+	_, err = fakeGate.Approve(intent.ID, "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, transfer, sentSingle)
+
+	batch.BatchReference = "TEST_BCH_REF_APPROVAL_TAMPERED"
+	batch.TransactionList = []params.SingleTransferParam{transfer}
+	intent, err = fakeGate.ProposeBulkTransfer("maker", batch)
+	assert.Nil(t, err)
+	batch.TransactionList[0].Amount, batch.TransactionList[0].AccountNumber = transfer.Amount*100, "9999999999"
+	intent.Bulk.TransactionList[0].Amount = transfer.Amount * 100
+	_, err = fakeGate.Approve(intent.ID, "alice")
+	assert.Nil(t, err)
+	if assert.Len(t, sentBulk.TransactionList, 1) {
+		assert.Equal(t, transfer, sentBulk.TransactionList[0])
+	}
+
+	// Of two approvals racing on the same version, the one saved last is denied with the conflict.
+	auditErr = nil
+	events = nil
+	var mu sync.Mutex
+	gate.Audit = AuditSinkFunc(func(event AuditEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return audit.Record(event)
+	})
+	transfer.Reference = "TEST_TRF_REF_APPROVAL_RACE"
+	intent, err = gate.ProposeSingleTransfer("maker", transfer)
+	assert.Nil(t, err)
+	racing := &barrierIntentStore{MemoryIntentStore: store, waiting: 2}
+	racing.read.Add(2)
+	gate.Store = racing
+
+	errs := make(chan error, 2)
+	for _, approver := range []string{"alice", "bob"} {
+		go func(approver string) {
+			_, err := gate.Approve(intent.ID, approver)
+			errs <- err
+		}(approver)
+	}
+	err1, err2 := <-errs, <-errs
+	assert.True(t, (err1 == nil) != (err2 == nil))
+	if err1 == nil {
+		err1 = err2
+	}
+	assert.Equal(t, ErrIntentConflict, err1)
+
+	intent, err = gate.Intent(intent.ID)
+	assert.Nil(t, err)
+	assert.Len(t, intent.Approvals, 1)
+	loser := "alice"
+	if intent.Approvals[0].ApproverID == "alice" {
+		loser = "bob"
+	}
+	var loserEvents []AuditEvent
+	for _, e := range events {
+		if e.ActorID == loser {
+			loserEvents = append(loserEvents, e)
+		}
+	}
+	if assert.Len(t, loserEvents, 2) {
+		assert.Equal(t, AuditApproved, loserEvents[0].Action)
+		assert.Equal(t, AuditDenied, loserEvents[1].Action)
+		assert.Equal(t, IntentPending, loserEvents[1].Status)
+		assert.Equal(t, ErrIntentConflict.Error(), loserEvents[1].Reason)
+	}
+}
+
+// barrierIntentStore holds the first reads of an intent until all of them were made, so that the callers race on
+// the same version.
+type barrierIntentStore struct {
+	*MemoryIntentStore
+	mu      sync.Mutex
+	waiting int
+	read    sync.WaitGroup
+}
+
+func (s *barrierIntentStore) Get(id string) (TransferIntent, bool, error) {
+	intent, found, err := s.MemoryIntentStore.Get(id)
+	s.mu.Lock()
+	wait := s.waiting > 0
+	if wait {
+		s.waiting--
+	}
+	s.mu.Unlock()
+	if wait {
+		s.read.Done()
+		s.read.Wait()
+	}
+	return intent, found, err
+}
+
+func TestTransfersCSV_Read(t *testing.T) {
+	file := `Ref,Amount (NGN),Bank,Account,Description
+PAY-001,"1,500.50",058,0123456789,October salary
+
+PAY-002,2000,058,0123456789,
+PAY-003,abc,058,0123456789,Bonus
+PAY-004,100,058,12345,Bonus
+PAY-001,100,058,0123456789,Bonus
+`
+	columns := CSVColumns{Amount: "amount (ngn)", BankCode: "BANK", AccountNumber: "account", Narration: "description", Reference: "ref"}
+	transfers, rowErrs, err := ReadTransfersCSV(strings.NewReader(file), columns, "Salary")
+	assert.Nil(t, err)
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, params.SingleTransferParam{
+		Amount:        params.Kobo(150050),
+		Reference:     "PAY-001",
+		Narration:     "October salary",
+		BankCode:      "058",
+		AccountNumber: "0123456789",
+		Currency:      CurrencyNGN,
+	}, transfers[0])
+	assert.Equal(t, "Salary", transfers[1].Narration)
+
+	assert.Len(t, rowErrs, 3)
+	assert.Equal(t, 5, rowErrs[0].Row)
+	assert.Contains(t, rowErrs[0].Error(), "amount")
+	assert.Equal(t, 6, rowErrs[1].Row)
+	var validationErrs ValidationErrors
+	assert.True(t, errors.As(rowErrs[1], &validationErrs))
+	_, found := validationErrs.Field("accountNumber")
+	assert.True(t, found)
+	assert.Equal(t, 7, rowErrs[2].Row)
+	assert.Contains(t, rowErrs[2].Error(), "row 2")
+
+	// Rows that are not valid CSV are listed and the rest of the file is still read.
+	malformed := `Ref,Amount (NGN),Bank,Account,Description
+PAY-001,100,058,0123456789,Bonus
+PAY-002,100,058,0123456789,"Bonus" for October
+PAY-003,100,058,0123456789,Bonus
+`
+	transfers, rowErrs, err = ReadTransfersCSV(strings.NewReader(malformed), columns, "Salary")
+	assert.Nil(t, err)
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, "PAY-003", transfers[1].Reference)
+	assert.Len(t, rowErrs, 1)
+	assert.Equal(t, 3, rowErrs[0].Row)
+	assert.True(t, errors.Is(rowErrs[0], csv.ErrQuote))
+
+	_, _, err = ReadTransfersCSV(strings.NewReader(file), DefaultCSVColumns, "Salary")
+	assert.NotNil(t, err)
+	_, _, err = ReadTransfersCSV(strings.NewReader(""), columns, "Salary")
+	assert.NotNil(t, err)
+}
+
+func TestTransfersCSV_Export(t *testing.T) {
+	mockAPIServer.Reset()
+	var buf bytes.Buffer
+	assert.Nil(t, ExportBulkTransferCSV(client.Disbursements, testhelpers.BatchReference, &buf))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, TransfersCSVHeader, records[0])
+	tx, _ := client.Disbursements.BulkTransferTransactions(testhelpers.BatchReference, 0, 10)
+	assert.Len(t, records, len(tx.ResponseBody.Content)+1)
+	first := tx.ResponseBody.Content[0]
+	assert.Equal(t, []string{
+		first.Reference, first.Amount.String(), first.Fee.String(), first.Currency, first.BankCode, first.BankName,
+		first.AccountNumber, first.AccountName, first.Narration, string(first.Status), first.DateCreated.Raw(),
+	}, records[1])
+
+	assert.NotNil(t, ExportBulkTransferCSV(client.Disbursements, "TEST_UNKNOWN_BATCH", &buf))
+
+	// Every page of the batch is exported.
+	var pagesFetched []int
+	fake := &FakeDisbursements{
+		BulkTransferTransactionsFunc: func(batchReference string, pageNo int, pageSize int) (*TransferTransactionsResponse, error) {
+			pagesFetched = append(pagesFetched, pageNo)
+			page := &TransferTransactionsResponse{}
+			page.ResponseBody.Content = []SingleTransferDetails{{Reference: fmt.Sprintf("%v-%d", batchReference, pageNo)}}
+			page.ResponseBody.TotalPages = 2
+			page.ResponseBody.Last = pageNo == 1
+			return page, nil
+		},
+	}
+	buf.Reset()
+	assert.Nil(t, ExportBulkTransferCSV(fake, "TEST_BCH_REF_EXPORT", &buf))
+	assert.Equal(t, []int{0, 1}, pagesFetched)
+	records, err = csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, "TEST_BCH_REF_EXPORT-0", records[1][0])
+	assert.Equal(t, "TEST_BCH_REF_EXPORT-1", records[2][0])
+
+	// Cells that a spreadsheet would run as formulas are neutralised.
+	buf.Reset()
+	assert.Nil(t, WriteTransfersCSV(&buf, []SingleTransferDetails{{
+		Reference:   "TEST_TRF_REF_FORMULA",
+		Amount:      testhelpers.Amount,
+		BankName:    "+2348012345678",
+		AccountName: "@SUM(A1:A9)",
+		Narration:   `=HYPERLINK("http://example.com","Click")`,
+		Status:      TransferStatusSuccess,
+	}}))
+	records, err = csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, "TEST_TRF_REF_FORMULA", records[1][0])
+	assert.Equal(t, testhelpers.Amount.String(), records[1][1])
+	assert.Equal(t, "'+2348012345678", records[1][5])
+	assert.Equal(t, "'@SUM(A1:A9)", records[1][7])
+	assert.Equal(t, `'=HYPERLINK("http://example.com","Click")`, records[1][8])
+	assert.Equal(t, string(TransferStatusSuccess), records[1][9])
+}
+
+func TestTransferStatus(t *testing.T) {
+	assert.True(t, TransferStatusSuccess.IsFinal())
+	assert.True(t, TransferStatusReversed.IsFinal())
+	assert.False(t, TransferStatusPending.IsFinal())
+	assert.False(t, TransferStatusPendingAuthorization.IsFinal())
+	assert.True(t, TransferStatusSuccess.IsSuccessful())
+	assert.False(t, TransferStatusReversed.IsSuccessful())
+	assert.True(t, TransferStatusPendingAuthorization.RequiresAuthorization())
+	assert.True(t, TransferStatusOTPEmailDispatchFailed.RequiresAuthorization())
+	assert.False(t, TransferStatusPending.RequiresAuthorization())
+	assert.False(t, TransferStatus("SETTLED").IsKnown())
+
+	assert.Nil(t, TransferStatus("").ValidateTransition(TransferStatusPending))
+	assert.Nil(t, TransferStatusPendingAuthorization.ValidateTransition(TransferStatusPending))
+	assert.Nil(t, TransferStatusPending.ValidateTransition(TransferStatusSuccess))
+	assert.Nil(t, TransferStatusSuccess.ValidateTransition(TransferStatusSuccess))
+	assert.Nil(t, TransferStatusSuccess.ValidateTransition(TransferStatusReversed))
+	assert.NotNil(t, TransferStatusSuccess.ValidateTransition(TransferStatusPending))
+	assert.NotNil(t, TransferStatusFailed.ValidateTransition(TransferStatusSuccess))
+	assert.NotNil(t, TransferStatusPending.ValidateTransition(TransferStatusReversed))
+	assert.NotNil(t, TransferStatusPending.ValidateTransition("SETTLED"))
+
+	err := TransferStatusReversed.ValidateTransition(TransferStatusSuccess)
+	var transitionErr *StatusTransitionError
+	assert.True(t, errors.As(err, &transitionErr))
+	assert.Equal(t, "REVERSED", transitionErr.From)
+	assert.Equal(t, "SUCCESS", transitionErr.To)
+}
+
+func TestBatchStatus(t *testing.T) {
+	assert.True(t, BatchStatusCompleted.IsFinal())
+	assert.True(t, BatchStatusCompleted.IsSuccessful())
+	assert.False(t, BatchStatusInProgress.IsFinal())
+	assert.True(t, BatchStatusPendingAuthorization.RequiresAuthorization())
+
+	assert.Nil(t, BatchStatusPendingAuthorization.ValidateTransition(BatchStatusAwaitingProcessing))
+	assert.Nil(t, BatchStatusAwaitingProcessing.ValidateTransition(BatchStatusCompleted))
+	assert.NotNil(t, BatchStatusCompleted.ValidateTransition(BatchStatusInProgress))
+	assert.NotNil(t, BatchStatusInProgress.ValidateTransition(BatchStatusPendingAuthorization))
+}
+
 func TestGeneral_GetTransaction(t *testing.T) {
+	mockAPIServer.Reset()
 	tx, err := client.General.GetTransaction(testhelpers.TransferReference)
 	assert.Nil(t, err)
 	assert.Equal(t, PaymentStatusPaid, tx.ResponseBody.PaymentStatus)
-	assert.Equal(t, fmt.Sprintf("%v", testhelpers.Amount), tx.ResponseBody.AmountPaid)
+	assert.Equal(t, testhelpers.Amount, tx.ResponseBody.AmountPaid)
 }
 
 func TestGeneral_VerifyTransaction(t *testing.T) {
+	mockAPIServer.Reset()
 	//Set Secret Key To Be Test Environemnt Secret Key
-	client.General.Config.SecretKey = testhelpers.SecretKey
+	client.General.(*general).Config.SecretKey = testhelpers.SecretKey
 	tx, _ := client.General.GetTransaction(testhelpers.TransferReference)
 	assert.True(t, client.General.VerifyTransaction(&tx.ResponseBody, false))
 	assert.True(t, client.General.VerifyTransaction(&tx.ResponseBody, true))
 }
 
+func TestGeneral_VerifyTransactionAmountFormat(t *testing.T) {
+	mockAPIServer.Reset()
+	// The hash is computed from the amount exactly as Monnify sent it, whatever its format.
+	for _, amountPaid := range []string{`"100.0"`, `"100"`, `100.0`, `100.00`} {
+		payload := strings.Replace(testhelpers.FakeInflowNotificationPayload(), `"amountPaid": "100.00"`, `"amountPaid": `+amountPaid, 1)
+		var tx GeneralTransaction
+		assert.Nil(t, json.Unmarshal([]byte(payload), &tx))
+		assert.Equal(t, params.Naira(100), tx.AmountPaid)
+		assert.Equal(t, strings.Trim(amountPaid, `"`), tx.AmountPaidRaw())
+
+		h := sha512.Sum512([]byte(testhelpers.SecretKey + "|" + tx.PaymentReference + "|" + tx.AmountPaidRaw() + "|" + tx.PaidOn.Raw() + "|" + tx.TransactionReference))
+		tx.TransactionHash = hex.EncodeToString(h[:])
+		assert.True(t, client.General.VerifyTransaction(&tx, false), amountPaid)
+
+		tx.AmountPaid = params.Naira(101)
+		assert.False(t, client.General.VerifyTransaction(&tx, false), amountPaid)
+	}
+
+	// Other formats of the same amount do not match the hash.
+	var tx GeneralTransaction
+	assert.Nil(t, json.Unmarshal([]byte(testhelpers.FakeInflowNotificationPayload()), &tx))
+	h := sha512.Sum512([]byte(testhelpers.SecretKey + "|" + tx.PaymentReference + "|100|" + tx.PaidOn.Raw() + "|" + tx.TransactionReference))
+	tx.TransactionHash = hex.EncodeToString(h[:])
+	assert.False(t, client.General.VerifyTransaction(&tx, false))
+}
+
+func TestGeneral_InitializeTransaction(t *testing.T) {
+	mockAPIServer.Reset()
+	res, err := client.General.InitializeTransaction(params.InitializeTransactionParam{
+		Amount:             params.Naira(2500),
+		CustomerName:       testhelpers.CustomerName,
+		CustomerEmail:      testhelpers.CustomerEmail,
+		PaymentReference:   "TEST_PAY_REF_INIT",
+		PaymentDescription: "Order ORD-1",
+		MetaData:           Metadata{"orderId": "ORD-1", "quantity": 3, "gift": true},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "TEST_PAY_REF_INIT", res.ResponseBody.PaymentReference)
+	assert.NotEmpty(t, res.ResponseBody.CheckoutURL)
+
+	var sent params.InitializeTransactionParam
+	assert.Nil(t, mockAPIServer.LastRequestBody("/v1/merchant/transactions/init-transaction", &sent))
+	assert.Equal(t, testConfig.DefaultContractCode, sent.ContractCode)
+	assert.Equal(t, CurrencyNGN, sent.CurrencyCode)
+
+	assert.True(t, mockAPIServer.PayTransaction(res.ResponseBody.TransactionReference))
+	tx, err := client.General.GetTransaction(res.ResponseBody.TransactionReference)
+	assert.Nil(t, err)
+	assert.Equal(t, params.Naira(2500), tx.ResponseBody.AmountPaid)
+
+	orderId, ok := tx.ResponseBody.MetaData.String("orderId")
+	assert.True(t, ok)
+	assert.Equal(t, "ORD-1", orderId)
+	quantity, ok := tx.ResponseBody.MetaData.Int("quantity")
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), quantity)
+	gift, ok := tx.ResponseBody.MetaData.Bool("gift")
+	assert.True(t, ok)
+	assert.True(t, gift)
+	assert.True(t, client.General.VerifyTransaction(&tx.ResponseBody, true))
+
+	_, err = client.General.InitializeTransaction(params.InitializeTransactionParam{
+		Amount:             params.Naira(2500),
+		CustomerName:       testhelpers.CustomerName,
+		CustomerEmail:      testhelpers.CustomerEmail,
+		PaymentReference:   "TEST_PAY_REF_INIT",
+		PaymentDescription: "Order ORD-1",
+	})
+	assert.NotNil(t, err)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/merchant/transactions/init-transaction", 2))
+}
+
+func TestMetadata_Accessors(t *testing.T) {
+	var tx GeneralTransaction
+	assert.Nil(t, json.Unmarshal([]byte(testhelpers.FakeInflowNotificationPayload()), &tx))
+
+	name, ok := tx.MetaData.String("name")
+	assert.True(t, ok)
+	assert.Equal(t, "Damilare", name)
+	age, ok := tx.MetaData.Int("age")
+	assert.True(t, ok)
+	assert.Equal(t, int64(45), age)
+	_, ok = tx.MetaData.Int("name")
+	assert.False(t, ok)
+	_, ok = tx.MetaData.String("missing")
+	assert.False(t, ok)
+
+	var decoded struct {
+		Name string `json:"name"`
+		Age  string `json:"age"`
+	}
+	assert.Nil(t, tx.MetaData.Decode(&decoded))
+	assert.Equal(t, "45", decoded.Age)
+}
+
 func TestGeneral_GetBanks(t *testing.T) {
+	mockAPIServer.Reset()
 	b, err := client.General.GetBanks()
 	assert.Nil(t, err)
-	assert.Equal(t, 2, len(b.ResponseBody))
+	assert.Equal(t, 3, len(b.ResponseBody))
 	assert.NotEmpty(t, b.ResponseBody[0].Name)
 
 	bc, err := client.General.GetBanksUseCache()
 	assert.Nil(t, err)
-	assert.Equal(t, 2, len(bc.ResponseBody))
+	assert.Equal(t, 3, len(bc.ResponseBody))
 	assert.NotEmpty(t, bc.ResponseBody[0].Name)
 
 	client.General.InvalidateBankCache()
 	bc, err = client.General.GetBanksUseCache()
 	assert.Nil(t, err)
-	assert.Equal(t, 2, len(bc.ResponseBody))
+	assert.Equal(t, 3, len(bc.ResponseBody))
+}
+
+// Verification Tests
+func TestVerification_MatchBVNAndAccount(t *testing.T) {
+	mockAPIServer.Reset()
+	opts := params.BVNAccountMatchParam{
+		BVN:           testhelpers.BVN,
+		AccountNumber: testhelpers.AccountNumber,
+		BankCode:      testhelpers.BankCode,
+	}
+	m, err := client.Verification.MatchBVNAndAccount(opts)
+	assert.Nil(t, err)
+	assert.Equal(t, testhelpers.BVN, m.ResponseBody.BVN)
+	assert.Equal(t, testhelpers.AccountName, m.ResponseBody.AccountName)
+	assert.Equal(t, MatchStatusFull, m.ResponseBody.MatchStatus)
+	assert.Equal(t, float64(100), m.ResponseBody.MatchPercentage)
+}
+
+func TestVerification_VerifyBVN(t *testing.T) {
+	mockAPIServer.Reset()
+	opts := params.BVNDetailsParam{
+		BVN:         testhelpers.BVN,
+		Name:        testhelpers.CustomerName,
+		DateOfBirth: testhelpers.BVNDateOfBirth,
+		MobileNo:    testhelpers.MobileNo,
+	}
+	v, err := client.Verification.VerifyBVN(opts)
+	assert.Nil(t, err)
+	assert.Equal(t, testhelpers.BVN, v.ResponseBody.BVN)
+	assert.Equal(t, MatchStatusPartial, v.ResponseBody.Name.MatchStatus)
+	assert.Equal(t, float64(75), v.ResponseBody.Name.MatchPercentage)
+	assert.Equal(t, MatchStatusFull, v.ResponseBody.DateOfBirth)
+	assert.Equal(t, MatchStatusNone, v.ResponseBody.MobileNo)
+}
+
+// Wallet Tests
+func TestWallets_Create(t *testing.T) {
+	mockAPIServer.Reset()
+	customer := params.WalletCustomerParam{
+		Name:  testhelpers.CustomerName,
+		Email: testhelpers.CustomerEmail,
+		BVNDetails: params.WalletBVNParam{
+			BVN:         testhelpers.BVN,
+			DateOfBirth: "1993-10-03",
+		},
+	}
+	w, err := client.Wallets.Create(testhelpers.WalletReference, testhelpers.WalletName, customer)
+	assert.Nil(t, err)
+	assert.Equal(t, testhelpers.WalletReference, w.ResponseBody.WalletReference)
+	assert.Equal(t, testhelpers.WalletName, w.ResponseBody.WalletName)
+	assert.Equal(t, testhelpers.BVN, w.ResponseBody.BVNDetails.BVN)
+	assert.Equal(t, testhelpers.WalletAccountNumber, w.ResponseBody.AccountNumber)
+
+	_, err = client.Wallets.Create("", testhelpers.WalletName, customer)
+	assert.NotNil(t, err)
+}
+
+func TestWallets_List(t *testing.T) {
+	mockAPIServer.Reset()
+	customer := params.WalletCustomerParam{
+		Name:       testhelpers.CustomerName,
+		Email:      testhelpers.CustomerEmail,
+		BVNDetails: params.WalletBVNParam{BVN: testhelpers.BVN, DateOfBirth: "1993-10-03"},
+	}
+	_, err := client.Wallets.Create(testhelpers.WalletReference, testhelpers.WalletName, customer)
+	assert.Nil(t, err)
+
+	w, err := client.Wallets.List(testhelpers.CustomerEmail, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(w.ResponseBody.Content))
+	assert.Equal(t, testhelpers.CustomerEmail, w.ResponseBody.Content[0].CustomerEmail)
+	assert.Equal(t, testhelpers.WalletReference, w.ResponseBody.Content[0].WalletReference)
+}
+
+// Mock Server Fault Tests
+func TestMockServer_Faults(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetFaults()
+
+	mockAPIServer.FailNext("/v1/disbursements/wallet-balance", 2, http.StatusServiceUnavailable, "D99", "Service unavailable")
+	for i := 0; i < 2; i++ {
+		_, err := client.Disbursements.WalletBalance(testhelpers.WalletId)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "503")
+		assert.Contains(t, err.Error(), "D99")
+	}
+	_, err := client.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.Nil(t, err)
+
+	mockAPIServer.MalformNext("/v1/bank-transfer/reserved-accounts/{accountReference}", 1)
+	_, err = client.ReservedAccounts.Details(testhelpers.AccountReference)
+	assert.NotNil(t, err)
+
+	mockAPIServer.DropNext(testhelpers.AnyRoute, 1)
+	_, err = client.Disbursements.ResendOTP(testhelpers.TransferReference)
+	assert.NotNil(t, err)
+	_, err = client.Disbursements.ResendOTP(testhelpers.TransferReference)
+	assert.Nil(t, err)
+
+	mockAPIServer.SetLatency("/v1/banks", 50*time.Millisecond)
+	start := time.Now()
+	_, err = client.General.GetBanks()
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	mockAPIServer.SetLatency("/v1/banks", 0)
+}
+
+func TestMockServer_DropResponse(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetFaults()
+
+	opts := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_DROPPED",
+		Narration:     "TEST",
+		BankCode:      testhelpers.BankCode,
+		AccountNumber: testhelpers.AccountNumber,
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, testhelpers.Amount+testhelpers.TransferFee))
+	mockAPIServer.InjectFault("/v1/disbursements/single", 1, testhelpers.Fault{DropResponse: true})
+	_, err := client.Disbursements.SingleTransfer(opts)
+	assert.NotNil(t, err)
+
+	d, err := client.Disbursements.SingleTransferDetails(opts.Reference)
+	assert.Nil(t, err)
+	assert.Equal(t, opts.Reference, d.ResponseBody.Reference)
+}
+
+func TestMockServer_ExpireToken(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetFaults()
+	defer mockAPIServer.ResetRequests()
+
+	_, err := client.ReservedAccounts.Details(testhelpers.AccountReference)
+	assert.Nil(t, err)
+
+	mockAPIServer.ResetRequests()
+	mockAPIServer.ExpireToken()
+	_, err = client.ReservedAccounts.Details(testhelpers.AccountReference)
+	assert.Nil(t, err)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/auth/login", 1))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/bank-transfer/reserved-accounts/{accountReference}", 2))
+
+	mockAPIServer.ResetRequests()
+	mockAPIServer.ExpireToken()
+	opts := params.BVNAccountMatchParam{BVN: testhelpers.BVN, AccountNumber: testhelpers.AccountNumber, BankCode: testhelpers.BankCode}
+	_, err = client.Verification.MatchBVNAndAccount(opts)
+	assert.Nil(t, err)
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/vas/bvn-account-match", 2))
+	for _, rr := range mockAPIServer.RequestsTo("/v1/vas/bvn-account-match") {
+		var sent params.BVNAccountMatchParam
+		assert.Nil(t, rr.Decode(&sent))
+		assert.Equal(t, opts, sent)
+	}
+
+	mockAPIServer.ResetRequests()
+	mockAPIServer.FailNext("/v1/bank-transfer/reserved-accounts/{accountReference}", 2, http.StatusUnauthorized, "99", "Unauthorized")
+	_, err = client.ReservedAccounts.Details(testhelpers.AccountReference)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/auth/login", 1))
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/bank-transfer/reserved-accounts/{accountReference}", 2))
+
+	mockAPIServer.ResetRequests()
+	mockAPIServer.FailNext("/v1/disbursements/wallet-balance", 1, http.StatusUnauthorized, "99", "Unauthorized")
+	_, err = client.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.NotNil(t, err)
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/auth/login"))
+}
+
+// Mock Server Recording Tests
+func TestMockServer_RecordsRequests(t *testing.T) {
+	mockAPIServer.Reset()
+	mockAPIServer.ResetRequests()
+	defer mockAPIServer.ResetRequests()
+
+	opts := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_RECORDED",
+		Narration:     "TEST",
+		BankCode:      testhelpers.BankCode,
+		AccountNumber: testhelpers.AccountNumber,
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	assert.True(t, mockAPIServer.CreditWallet(testhelpers.WalletId, testhelpers.Amount+testhelpers.TransferFee))
+	_, err := client.Disbursements.SingleTransfer(opts)
+	assert.Nil(t, err)
+
+	assert.Nil(t, mockAPIServer.AssertCalled("/v1/disbursements/single", 1))
+	assert.NotNil(t, mockAPIServer.AssertCalled("/v1/disbursements/single", 2))
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/batch"))
+
+	var sent params.SingleTransferParam
+	assert.Nil(t, mockAPIServer.LastRequestBody("/v1/disbursements/single", &sent))
+	assert.Equal(t, opts, sent)
+
+	rr := mockAPIServer.RequestsTo("/v1/disbursements/single")[0]
+	assert.Equal(t, http.MethodPost, rr.Method)
+	assert.True(t, strings.HasPrefix(rr.Header.Get("Authorization"), "Basic "))
+
+	_, err = client.ReservedAccounts.Details(testhelpers.AccountReference)
+	assert.Nil(t, err)
+	rr = mockAPIServer.RequestsTo("/v1/bank-transfer/reserved-accounts/{accountReference}")[0]
+	assert.Equal(t, "/v1/bank-transfer/reserved-accounts/"+testhelpers.AccountReference, rr.Path)
+	assert.True(t, strings.HasPrefix(rr.Header.Get("Authorization"), "Bearer "))
+
+	_, err = client.Disbursements.SingleTransferDetails(opts.Reference)
+	assert.Nil(t, err)
+	rr = mockAPIServer.RequestsTo("/v1/disbursements/single/summary")[0]
+	assert.Equal(t, opts.Reference, rr.Query.Get("reference"))
+}
+
+func TestMockServer_ValidatesAuth(t *testing.T) {
+	mockAPIServer.Reset()
+	req, _ := http.NewRequest(http.MethodGet, mockAPIServer.URL+"/v1/disbursements/wallet-balance?walletId="+testhelpers.WalletId, nil)
+	req.Header.Set("Authorization", "Bearer "+testhelpers.AccessToken)
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodGet, mockAPIServer.URL+"/v1/banks", nil)
+	res, err = http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	mockAPIServer.SetCredentials("WRONG_KEY", "WRONG_SECRET")
+	_, err = client.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.NotNil(t, err)
+	mockAPIServer.SetCredentials("", "")
+	_, err = client.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.Nil(t, err)
+}
+
+// Cassette Tests
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	mockAPIServer.Reset()
+	dir, err := ioutil.TempDir("", "gomonnify")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	cassette := filepath.Join(dir, "wallet_balance.json")
+
+	rec, err := testhelpers.NewRecorder(cassette, testhelpers.RecorderModeAuto)
+	assert.Nil(t, err)
+	assert.Equal(t, testhelpers.RecorderModeRecord, rec.Mode())
+	rec.Redact(testhelpers.SecretKey)
+
+	cfg := testConfig
+	cfg.Transport = rec
+	recordingClient, err := New(cfg)
+	assert.Nil(t, err)
+
+	recorded, err := recordingClient.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.Nil(t, err)
+	_, err = recordingClient.ReservedAccounts.Details(testhelpers.AccountReference)
+	assert.Nil(t, err)
+	assert.Nil(t, rec.Stop())
+
+	raw, err := ioutil.ReadFile(cassette)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(raw), testhelpers.SecretKey)
+	assert.NotContains(t, string(raw), testhelpers.AccessToken)
+	assert.NotContains(t, string(raw), "Basic ")
+	assert.Contains(t, string(raw), testhelpers.Redacted)
+
+	mockAPIServer.ResetRequests()
+	defer mockAPIServer.ResetRequests()
+
+	rep, err := testhelpers.NewRecorder(cassette, testhelpers.RecorderModeAuto)
+	assert.Nil(t, err)
+	assert.Equal(t, testhelpers.RecorderModeReplay, rep.Mode())
+	rep.Redact(testhelpers.SecretKey)
+
+	cfg.Transport = rep
+	replayingClient, err := New(cfg)
+	assert.Nil(t, err)
+
+	replayed, err := replayingClient.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.Nil(t, err)
+	assert.Equal(t, recorded.ResponseBody, replayed.ResponseBody)
+	assert.Nil(t, mockAPIServer.AssertNotCalled("/v1/disbursements/wallet-balance"))
+
+	_, err = replayingClient.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.NotNil(t, err)
+}
+
+// Webhook Simulator Tests
+func TestWebhookEvent_SendTo(t *testing.T) {
+	type notification struct {
+		EventType string          `json:"eventType"`
+		EventData json.RawMessage `json:"eventData"`
+	}
+	var received []notification
+
+	handler := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get(testhelpers.WebhookSignatureHeader) != testhelpers.SignWebhookPayload(testhelpers.SecretKey, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var n notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, n)
+	}))
+	defer handler.Close()
+
+	eventTypes := []testhelpers.WebhookEventType{
+		testhelpers.WebhookEventCollection,
+		testhelpers.WebhookEventDisbursementSuccess,
+		testhelpers.WebhookEventDisbursementFailure,
+		testhelpers.WebhookEventDisbursementReversal,
+		testhelpers.WebhookEventRefundSuccess,
+		testhelpers.WebhookEventRefundFailure,
+		testhelpers.WebhookEventSettlement,
+		testhelpers.WebhookEventMandate,
+	}
+	for _, et := range eventTypes {
+		assert.Nil(t, testhelpers.NewWebhookEvent(et).SendTo(handler.URL))
+	}
+	assert.Equal(t, len(eventTypes), len(received))
+
+	err := testhelpers.NewWebhookEvent(testhelpers.WebhookEventCollection).WithSecretKey("WRONG_SECRET").SendTo(handler.URL)
+	assert.NotNil(t, err)
+
+	received = nil
+	err = testhelpers.NewWebhookEvent(testhelpers.WebhookEventDisbursementFailure).
+		WithReference("TEST_WEBHOOK_REF").
+		WithAmount(params.Naira(2500)).
+		WithField("narration", "Payroll").
+		SendTo(handler.URL)
+	assert.Nil(t, err)
+
+	var transfer SingleTransferDetails
+	assert.Nil(t, json.Unmarshal(received[0].EventData, &transfer))
+	assert.Equal(t, "TEST_WEBHOOK_REF", transfer.Reference)
+	assert.Equal(t, params.Naira(2500), transfer.Amount)
+	assert.Equal(t, TransferStatusFailed, transfer.Status)
+	assert.Equal(t, "Payroll", transfer.Narration)
+}
+
+func TestWebhookEvent_CollectionVerifies(t *testing.T) {
+	payload, err := testhelpers.NewWebhookEvent(testhelpers.WebhookEventCollection).
+		WithReference("MNFY|TEST_WEBHOOK").
+		WithPaymentReference("ORDER-1").
+		WithAmount(params.Kobo(150050)).
+		WithCustomer("Jane Doe", "jane@tester.com").
+		Payload()
+	assert.Nil(t, err)
+
+	var n struct {
+		EventData GeneralTransaction `json:"eventData"`
+	}
+	assert.Nil(t, json.Unmarshal(payload, &n))
+	assert.Equal(t, "ORDER-1", n.EventData.PaymentReference)
+	assert.Equal(t, "jane@tester.com", n.EventData.Customer.Email)
+
+	m, _ := New(testConfig)
+	assert.True(t, m.General.VerifyTransaction(&n.EventData, false))
+}
+
+func TestFakes(t *testing.T) {
+	fake := &FakeDisbursements{
+		SingleTransferFunc: func(param params.SingleTransferParam) (*SingleTransferResponse, error) {
+			r := &SingleTransferResponse{}
+			r.ResponseBody.Reference = param.Reference
+			r.ResponseBody.Status = "SUCCESS"
+			return r, nil
+		},
+	}
+	m := &Monnify{Disbursements: fake}
+
+	res, err := m.Disbursements.SingleTransfer(params.SingleTransferParam{Reference: "FAKE_REF", Amount: params.Naira(100)})
+	assert.Nil(t, err)
+	assert.Equal(t, "FAKE_REF", res.ResponseBody.Reference)
+
+	calls := fake.SingleTransferCalls()
+	assert.Len(t, calls, 1)
+	assert.Equal(t, "FAKE_REF", calls[0].Param.Reference)
+	assert.Len(t, fake.ResendOTPCalls(), 0)
+
+	assert.Panics(t, func() { m.Disbursements.ResendOTP("FAKE_REF") })
+}
+
+// Timestamp Tests
+func TestTimestamp_Parse(t *testing.T) {
+	formats := map[string]string{
+		"2020-07-26 19:24:39.113":      "2020-07-26T19:24:39.113+01:00",
+		"26/02/2020 09:38:13 AM":       "2020-02-26T09:38:13+01:00",
+		"26/02/2020 09:38:13 PM":       "2020-02-26T21:38:13+01:00",
+		"2019-07-24T14:12:28.000+0000": "2019-07-24T15:12:28+01:00",
+		"2019-07-24T14:12:28Z":         "2019-07-24T15:12:28+01:00",
+		"2020-07-26":                   "2020-07-26T00:00:00+01:00",
+	}
+	for raw, expected := range formats {
+		ts, err := ParseTimestamp(raw)
+		assert.Nil(t, err, raw)
+		assert.Equal(t, expected, ts.Format(time.RFC3339Nano), raw)
+		assert.Equal(t, raw, ts.Raw())
+	}
+
+	_, err := ParseTimestamp("yesterday")
+	assert.NotNil(t, err)
+}
+
+func TestTimestamp_JSON(t *testing.T) {
+	mockAPIServer.Reset()
+	var v struct {
+		A Timestamp `json:"a"`
+		B Timestamp `json:"b"`
+		C Timestamp `json:"c"`
+		D Timestamp `json:"d"`
+	}
+	in := `{"a":"26/02/2020 09:38:13 AM","b":1595791479113,"c":"not a date","d":null}`
+	assert.Nil(t, json.Unmarshal([]byte(in), &v))
+	assert.Equal(t, 2020, v.A.Year())
+	assert.True(t, time.Date(2020, 7, 26, 19, 24, 39, 113000000, time.UTC).Equal(v.B.Time))
+	assert.True(t, v.C.IsZero())
+	assert.Equal(t, "not a date", v.C.Raw())
+	assert.True(t, v.D.IsZero())
+
+	out, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":"26/02/2020 09:38:13 AM","b":1595791479113,"c":"not a date","d":null}`, string(out))
+
+	tx, err := client.ReservedAccounts.Transactions(testhelpers.AccountReference, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, testhelpers.CreatedOn, tx.ResponseBody.Content[0].CreatedOn.Raw())
+	assert.Equal(t, 2020, tx.ResponseBody.Content[0].CreatedOn.Year())
+	assert.False(t, tx.ResponseBody.Content[0].CompletedOn.IsZero())
+}
+
+// Validation Tests
+
+func TestValidation_BeforeSending(t *testing.T) {
+	mockAPIServer.Reset()
+	m, _ := New(testConfig)
+	calls := len(mockAPIServer.RequestsTo("/v1/disbursements/single"))
+
+	opts := params.SingleTransferParam{
+		Amount:        testhelpers.Amount,
+		Reference:     "TEST_TRF_REF_VALIDATION",
+		Narration:     "TEST",
+		BankCode:      "999",
+		AccountNumber: "123",
+		Currency:      CurrencyNGN,
+		WalletId:      testhelpers.WalletId,
+	}
+	_, err := m.Disbursements.SingleTransfer(opts)
+	assert.IsType(t, ValidationErrors{}, err)
+	assert.Len(t, err.(ValidationErrors), 1)
+
+	// unknown bank codes are only caught once the bank list is cached
+	_, err = m.General.GetBanksUseCache()
+	assert.Nil(t, err)
+	opts.AccountNumber = testhelpers.AccountNumber
+	_, err = m.Disbursements.SingleTransfer(opts)
+	errs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	ve, found := errs.Field("bankCode")
+	assert.True(t, found)
+	assert.Equal(t, "bankCode 999 is not a known bank code", ve.Error())
+	assert.Len(t, mockAPIServer.RequestsTo("/v1/disbursements/single"), calls)
+
+	b, err := json.Marshal(params.ReserveAccountParam{AccountReference: testhelpers.AccountReference})
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "accountName")
+}
+
+func TestBase_EncodesRequestURL(t *testing.T) {
+	mockAPIServer.Reset()
+	mockAPIServer.ResetRequests()
+	defer mockAPIServer.ResetRequests()
+
+	_, err := client.Disbursements.SingleTransferDetails("TEST&pageNo=9")
+	assert.NotNil(t, err)
+	rr := mockAPIServer.RequestsTo("/v1/disbursements/single/summary")[0]
+	assert.Equal(t, "TEST&pageNo=9", rr.Query.Get("reference"))
+	assert.Equal(t, "", rr.Query.Get("pageNo"))
+
+	assert.Equal(t, "/v2/transactions/a%2Fb%3Fc", urlPath("/v2/transactions/%v", "a/b?c"))
+}
+
+func TestBase_UnsuccessfulResponse(t *testing.T) {
+	mockAPIServer.Reset()
+	defer mockAPIServer.ResetFaults()
+
+	mockAPIServer.FailNext("/v1/disbursements/wallet-balance", 1, http.StatusOK, "D01", "Wallet not active")
+	_, err := client.Disbursements.WalletBalance(testhelpers.WalletId)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusOK, apiErr.HTTPStatus)
+	assert.Equal(t, "D01", apiErr.ResponseCode)
+	assert.Equal(t, "Wallet not active", apiErr.ResponseMessage)
+
+	mockAPIServer.FailNext("/v1/disbursements/wallet-balance", 1, http.StatusServiceUnavailable, "D99", "Service unavailable")
+	_, err = client.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.HTTPStatus)
+
+	config := testConfig
+	config.AllowUnsuccessfulResponses = true
+	c, err := New(config)
+	assert.Nil(t, err)
+
+	mockAPIServer.FailNext("/v1/disbursements/wallet-balance", 1, http.StatusOK, "D01", "Wallet not active")
+	b, err := c.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.Nil(t, err)
+	assert.False(t, b.RequestSuccessful)
+	assert.Equal(t, "D01", b.ResponseCode)
+
+	mockAPIServer.FailNext("/v1/disbursements/wallet-balance", 1, http.StatusServiceUnavailable, "D99", "Service unavailable")
+	_, err = c.Disbursements.WalletBalance(testhelpers.WalletId)
+	assert.NotNil(t, err)
 }
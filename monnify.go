@@ -4,13 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jcobhams/gomonnify/params"
+	"os"
+	"strings"
 	"time"
 )
 
 const (
 	EnvSandbox Environment = "sandbox" //Sandbox environment for development
 	EnvLive    Environment = "live"    //Live environmrnt
-	EnvTest    Environment = "test"    //Test environment used during unit/integration testing
+	EnvTest    Environment = "test"    //Test environment used during unit/integration testing. Requires Config.BaseURL
 
 	SandBoxAPIKey       string = "MK_TEST_SAF7HR5F3F"
 	SandBoxSecretKey    string = "4SY6TNL8CK3VPRSBTHTRG2N8XXEGC6NL"
@@ -34,6 +36,9 @@ const (
 	NotificationInterval50  = params.NotificationInterval50
 	NotificationInterval100 = params.NotificationInterval100
 
+	PaymentMethodCard            = params.PaymentMethodCard
+	PaymentMethodAccountTransfer = params.PaymentMethodAccountTransfer
+
 	PaymentStatusPaid          string = "PAID"
 	PaymentStatusPending       string = "PENDING"
 	PaymentStatusOverpaid      string = "OVERPAID"
@@ -41,6 +46,32 @@ const (
 	PaymentStatusExpired       string = "EXPIRED"
 	PaymentStatusFailed        string = "FAILED"
 	PaymentStatusCancelled     string = "CANCELLED"
+
+	MatchStatusFull    MatchStatus = "FULL_MATCH"
+	MatchStatusPartial MatchStatus = "PARTIAL_MATCH"
+	MatchStatusNone    MatchStatus = "NO_MATCH"
+
+	TransferStatusPendingAuthorization   TransferStatus = "PENDING_AUTHORIZATION"
+	TransferStatusOTPEmailDispatchFailed TransferStatus = "OTP_EMAIL_DISPATCH_FAILED"
+	TransferStatusAwaitingProcessing     TransferStatus = "AWAITING_PROCESSING"
+	TransferStatusPending                TransferStatus = "PENDING"
+	TransferStatusInProgress             TransferStatus = "IN_PROGRESS"
+	TransferStatusSuccess                TransferStatus = "SUCCESS"
+	TransferStatusFailed                 TransferStatus = "FAILED"
+	TransferStatusReversed               TransferStatus = "REVERSED"
+	TransferStatusExpired                TransferStatus = "EXPIRED"
+
+	BatchStatusPendingAuthorization   BatchStatus = "PENDING_AUTHORIZATION"
+	BatchStatusOTPEmailDispatchFailed BatchStatus = "OTP_EMAIL_DISPATCH_FAILED"
+	BatchStatusAwaitingProcessing     BatchStatus = "AWAITING_PROCESSING"
+	BatchStatusInProgress             BatchStatus = "IN_PROGRESS"
+	BatchStatusCompleted              BatchStatus = "COMPLETED"
+	BatchStatusFailed                 BatchStatus = "FAILED"
+	BatchStatusExpired                BatchStatus = "EXPIRED"
+
+	WalletTransactionCredit WalletTransactionType = "CREDIT"
+	WalletTransactionDebit  WalletTransactionType = "DEBIT"
+	WalletTransactionFee    WalletTransactionType = "FEE"
 )
 
 var (
@@ -59,6 +90,10 @@ var (
 //New create a new instance of the Monnify struct based on provided config.
 //Returns a pointer to the struct and nil error if successful or a nil pointer and an error
 func New(config Config) (*Monnify, error) {
+	if config.TestModeFromEnv {
+		config = applyTestModeEnv(config)
+	}
+
 	if err := validateConfig(config); err != nil {
 		return nil, err
 	}
@@ -66,17 +101,23 @@ func New(config Config) (*Monnify, error) {
 	base := newBase(config)
 
 	m := &Monnify{
-		General:          &general{base, nil},
+		General:          &general{base},
 		Disbursements:    &disbursements{base},
 		ReservedAccounts: &reservedAccounts{base},
+		Verification:     &verification{base},
+		Wallets:          &wallets{base},
 	}
 	return m, nil
 }
 
 //validateConfig checks the provided config to ensure it's well formed
 func validateConfig(config Config) error {
-	if config.Environment != EnvSandbox && config.Environment != EnvLive {
-		return errors.New(fmt.Sprintf("malformed config - provided enviroment is not supported. - Only %v or %v is allowed", EnvLive, EnvSandbox))
+	if config.Environment != EnvSandbox && config.Environment != EnvLive && config.Environment != EnvTest {
+		return errors.New(fmt.Sprintf("malformed config - provided enviroment is not supported. - Only %v, %v or %v is allowed", EnvLive, EnvSandbox, EnvTest))
+	}
+
+	if config.Environment == EnvTest && config.BaseURL == "" {
+		return errors.New("malformed config - BaseURL is required in test environment")
 	}
 
 	if config.APIKey == "" {
@@ -97,6 +138,24 @@ func validateConfig(config Config) error {
 	return nil
 }
 
-func failedRequestMessage(httpCode int, monnifyCode interface{}, message string) error {
-	return errors.New(fmt.Sprintf("Request Failed - HTTP Status Code: %v | Monnify Status Code: %v | Message: %v", httpCode, monnifyCode, message))
+//applyTestModeEnv switches the config to the test environment when GOMONNIFY_TESTMODE is ON,
+//using GOMONNIFY_TESTURL as the base url unless one is already set. Only used when Config.TestModeFromEnv is set.
+func applyTestModeEnv(config Config) Config {
+	if tm, ok := os.LookupEnv("GOMONNIFY_TESTMODE"); !ok || strings.ToUpper(tm) != "ON" {
+		return config
+	}
+
+	config.Environment = EnvTest
+	if config.BaseURL == "" {
+		config.BaseURL = os.Getenv("GOMONNIFY_TESTURL")
+	}
+	return config
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Request Failed - HTTP Status Code: %v | Monnify Status Code: %v | Message: %v", e.HTTPStatus, e.ResponseCode, e.ResponseMessage)
+}
+
+func failedRequestMessage(httpCode int, monnifyCode string, message string) error {
+	return &APIError{HTTPStatus: httpCode, ResponseCode: monnifyCode, ResponseMessage: message}
 }
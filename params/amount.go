@@ -0,0 +1,186 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxAmountExponent bounds the exponent accepted by ParseAmount, far beyond the range of an Amount.
+const maxAmountExponent = 40
+
+// Amount is a monetary value held exactly as an integer number of minor units (kobo for NGN), so that sums, fees and
+// balances never pick up floating point rounding errors.
+// It marshals to JSON as a number with two decimal places (e.g 1500.50) and unmarshals from numbers and strings alike
+// ("1500", "1500.5", 1500.50), which is how Monnify sends amounts depending on the endpoint.
+type Amount int64
+
+// Naira returns an Amount of n whole units of the currency.
+func Naira(n int64) Amount {
+	return Amount(n * 100)
+}
+
+// Kobo returns an Amount of k minor units of the currency.
+func Kobo(k int64) Amount {
+	return Amount(k)
+}
+
+// AmountFromFloat converts f to an Amount, rounding half away from zero to the nearest minor unit.
+func AmountFromFloat(f float64) Amount {
+	return Amount(math.Round(f * 100))
+}
+
+// ParseAmount parses a decimal string such as "1500", "1500.5", "-20.05" or "1.5e3" exactly.
+// Digits beyond the second decimal place are only accepted when they are zeros.
+func ParseAmount(s string) (Amount, error) {
+	str := strings.TrimSpace(s)
+	exponent := 0
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.Atoi(str[i+1:])
+		if err != nil || e > maxAmountExponent || e < -maxAmountExponent {
+			return 0, fmt.Errorf("params: invalid amount %q", s)
+		}
+		str, exponent = str[:i], e
+	}
+
+	negative := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "-"), "+")
+
+	whole, fraction := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		whole, fraction = str[:i], str[i+1:]
+	}
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("params: invalid amount %q", s)
+	}
+	if strings.Trim(fraction, "0123456789") != "" || strings.Trim(whole, "0123456789") != "" {
+		return 0, fmt.Errorf("params: invalid amount %q", s)
+	}
+
+	// The exponent moves the decimal point, so 1.5e3 is parsed as 1500 and 1.00005e2 as 100.005.
+	if exponent > 0 {
+		fraction += strings.Repeat("0", exponent)
+		whole, fraction = whole+fraction[:exponent], fraction[exponent:]
+	} else if exponent < 0 {
+		whole = strings.Repeat("0", -exponent) + whole
+		whole, fraction = whole[:len(whole)+exponent], whole[len(whole)+exponent:]+fraction
+	}
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(fraction) > 2 {
+		if strings.Trim(fraction[2:], "0") != "" {
+			return 0, fmt.Errorf("params: amount %q has more than two decimal places", s)
+		}
+		fraction = fraction[:2]
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (math.MaxInt64-99)/100 {
+		return 0, fmt.Errorf("params: amount %q out of range", s)
+	}
+	minor, _ := strconv.ParseInt(fraction, 10, 64)
+
+	a := Amount(units*100 + minor)
+	if negative {
+		a = -a
+	}
+	return a, nil
+}
+
+// Kobo returns the amount in minor units.
+func (a Amount) Kobo() int64 {
+	return int64(a)
+}
+
+// Float64 returns the amount in whole units of the currency. Only use it for display or interop, never for arithmetic.
+func (a Amount) Float64() float64 {
+	return float64(a) / 100
+}
+
+// String formats the amount with exactly two decimal places, e.g 1500.50
+func (a Amount) String() string {
+	sign, v := "", int64(a)
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	return fmt.Sprintf("%v%d.%02d", sign, v/100, v%100)
+}
+
+// Add returns a + b.
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+// Sub returns a - b.
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+// Mul returns a multiplied by n, e.g the total of n transfers of the same amount.
+func (a Amount) Mul(n int64) Amount {
+	return a * Amount(n)
+}
+
+// Percent returns p percent of a, rounded half away from zero to the nearest minor unit.
+func (a Amount) Percent(p float64) Amount {
+	return Amount(math.Round(float64(a) * p / 100))
+}
+
+// Cmp returns -1 if a is less than b, 0 if they are equal and +1 if a is greater than b.
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether the amount is zero.
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// IsNegative reports whether the amount is less than zero.
+func (a Amount) IsNegative() bool {
+	return a < 0
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts JSON numbers, numeric strings, empty strings and null.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if str == "null" {
+		return nil
+	}
+	if strings.HasPrefix(str, `"`) {
+		unquoted, err := strconv.Unquote(str)
+		if err != nil {
+			return errors.New("params: invalid amount " + str)
+		}
+		if strings.TrimSpace(unquoted) == "" {
+			*a = 0
+			return nil
+		}
+		str = unquoted
+	}
+
+	parsed, err := ParseAmount(str)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
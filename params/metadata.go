@@ -0,0 +1,96 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Metadata holds arbitrary key/value pairs attached to a transaction at initialization (e.g order or customer ids)
+// and returned by Monnify on the transaction and its notifications.
+// Monnify may return values as strings even when they were sent as numbers or booleans, the typed accessors accept both.
+type Metadata map[string]interface{}
+
+// Get returns the raw value stored under key.
+func (m Metadata) Get(key string) (interface{}, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+// String returns the value stored under key as a string. Numbers and booleans are formatted.
+func (m Metadata) String(key string) (string, bool) {
+	switch v := m[key].(type) {
+	case string:
+		return v, true
+	case nil:
+		return "", false
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case json.Number:
+		return v.String(), true
+	case bool, int, int64:
+		return fmt.Sprintf("%v", v), true
+	}
+	return "", false
+}
+
+// Int returns the value stored under key as an integer. Numeric strings are parsed.
+func (m Metadata) Int(key string) (int64, bool) {
+	switch v := m[key].(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v), true
+		}
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// Float returns the value stored under key as a float. Numeric strings are parsed.
+func (m Metadata) Float(key string) (float64, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Bool returns the value stored under key as a boolean. "true" and "false" strings are parsed.
+func (m Metadata) Bool(key string) (bool, bool) {
+	switch v := m[key].(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	}
+	return false, false
+}
+
+// Decode copies the metadata into v, which is usually a pointer to a struct with json tags matching the keys.
+func (m Metadata) Decode(v interface{}) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -9,42 +9,46 @@ const (
 	NotificationInterval100  NotificationInterval   = 100
 
 	CurrencyNGN Currency = "NGN"
+
+	PaymentMethodCard            PaymentMethod = "CARD"
+	PaymentMethodAccountTransfer PaymentMethod = "ACCOUNT_TRANSFER"
 )
 
 type (
 	Currency               string
 	ValidationFailedOption string
 	NotificationInterval   int
+	PaymentMethod          string
 	ReserveAccountParam    struct {
-		AccountReference      string   `json:"accountReference, omitempty"`
-		AccountName           string   `json:"accountName, omitempty"`
-		CurrencyCode          Currency `json:"currencyCode, omitempty"`
-		ContractCode          string   `json:"contractCode, omitempty"`
-		CustomerEmail         string   `json:"customerEmail, omitempty"`
-		CustomerName          string   `json:"customerName, omitempty"`
-		RestrictPaymentSource bool     `json:"restrictPaymentSource, omitempty"`
-		incomeSplitConfig     []IncomeSplitConfigParam
-		AllowedPaymentSources AllowedPaymentSourcesParam
+		AccountReference      string                     `json:"accountReference,omitempty"`
+		AccountName           string                     `json:"accountName,omitempty"`
+		CurrencyCode          Currency                   `json:"currencyCode,omitempty"`
+		ContractCode          string                     `json:"contractCode,omitempty"`
+		CustomerEmail         string                     `json:"customerEmail,omitempty"`
+		CustomerName          string                     `json:"customerName,omitempty"`
+		RestrictPaymentSource bool                       `json:"restrictPaymentSource,omitempty"`
+		IncomeSplitConfig     []IncomeSplitConfigParam   `json:"incomeSplitConfig,omitempty"`
+		AllowedPaymentSources AllowedPaymentSourcesParam `json:"allowedPaymentSources,omitempty"`
 	}
 
 	IncomeSplitConfigParam struct {
-		SubAccountCode  string  `json:"subAccountCode, omitempty"`
-		FeePercentage   float64 `json:"feePercentage, omitempty"`
-		SplitPercentage float64 `json:"splitPercentage, omitempty"`
-		FeeBearer       bool    `json:"feeBearer, omitempty"`
+		SubAccountCode  string  `json:"subAccountCode,omitempty"`
+		FeePercentage   float64 `json:"feePercentage,omitempty"`
+		SplitPercentage float64 `json:"splitPercentage,omitempty"`
+		FeeBearer       bool    `json:"feeBearer,omitempty"`
 	}
 
 	AllowedPaymentSourcesParam struct {
 		BankAccounts []struct {
-			AccountNumber string `json:"accountNumber, omitempty"`
-			BankCode      string `json:"bankCode, omitempty"`
-		} `json:"bankAccounts, omitempty"`
+			AccountNumber string `json:"accountNumber,omitempty"`
+			BankCode      string `json:"bankCode,omitempty"`
+		} `json:"bankAccounts,omitempty"`
 
-		AccountNames []string `json:"accountNames, omitempty"`
+		AccountNames []string `json:"accountNames,omitempty"`
 	}
 
 	SingleTransferParam struct {
-		Amount        float64  `json:"amount"`
+		Amount        Amount   `json:"amount"`
 		Reference     string   `json:"reference"`
 		Narration     string   `json:"narration"`
 		BankCode      string   `json:"bankCode"`
@@ -62,4 +66,48 @@ type (
 		NotificationInterval NotificationInterval   `json:"notificationInterval"`
 		TransactionList      []SingleTransferParam  `json:"transactionList"`
 	}
+
+	BVNAccountMatchParam struct {
+		BVN           string `json:"bvn"`
+		AccountNumber string `json:"accountNumber"`
+		BankCode      string `json:"bankCode"`
+	}
+
+	// BVNDetailsParam holds the customer details to be checked against the BVN records.
+	// DateOfBirth is expected in the dd-MMM-yyyy format, e.g 03-Oct-1993
+	BVNDetailsParam struct {
+		BVN         string `json:"bvn"`
+		Name        string `json:"name"`
+		DateOfBirth string `json:"dateOfBirth"`
+		MobileNo    string `json:"mobileNo"`
+	}
+
+	// WalletCustomerParam describes the customer who owns a disbursement wallet.
+	WalletCustomerParam struct {
+		Name       string
+		Email      string
+		BVNDetails WalletBVNParam
+	}
+
+	// WalletBVNParam holds the BVN of the wallet owner. DateOfBirth is expected in the yyyy-MM-dd format.
+	WalletBVNParam struct {
+		BVN         string `json:"bvn"`
+		DateOfBirth string `json:"bvnDateOfBirth"`
+	}
+
+	// InitializeTransactionParam starts a collection through the Monnify checkout.
+	// ContractCode defaults to Config.DefaultContractCode and CurrencyCode to NGN when left empty.
+	// MetaData is returned as is on the transaction and its notifications, e.g to carry an order id.
+	InitializeTransactionParam struct {
+		Amount             Amount          `json:"amount"`
+		CustomerName       string          `json:"customerName"`
+		CustomerEmail      string          `json:"customerEmail"`
+		PaymentReference   string          `json:"paymentReference"`
+		PaymentDescription string          `json:"paymentDescription"`
+		CurrencyCode       Currency        `json:"currencyCode"`
+		ContractCode       string          `json:"contractCode"`
+		RedirectUrl        string          `json:"redirectUrl,omitempty"`
+		PaymentMethods     []PaymentMethod `json:"paymentMethods,omitempty"`
+		MetaData           Metadata        `json:"metaData,omitempty"`
+	}
 )
@@ -0,0 +1,120 @@
+package params
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAmount_JSON(t *testing.T) {
+	var v struct {
+		A Amount `json:"a"`
+		B Amount `json:"b"`
+		C Amount `json:"c"`
+		D Amount `json:"d"`
+		E Amount `json:"e"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"a": 100, "b": "1500.5", "c": 0.1, "d": "", "e": null}`), &v))
+	assert.Equal(t, Naira(100), v.A)
+	assert.Equal(t, Kobo(150050), v.B)
+	assert.Equal(t, Kobo(10), v.C)
+	assert.True(t, v.D.IsZero())
+	assert.True(t, v.E.IsZero())
+
+	b, err := json.Marshal(SingleTransferParam{Amount: Kobo(150050)})
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"amount":1500.50`)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"a": "ten"}`), &v))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"a": 1.005}`), &v))
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	a, err := ParseAmount("0.10")
+	assert.Nil(t, err)
+	sum := a.Add(Kobo(20))
+	assert.Equal(t, "0.30", sum.String())
+	assert.Equal(t, "-0.05", Kobo(25).Sub(Kobo(30)).String())
+	assert.Equal(t, Naira(300), Naira(100).Mul(3))
+	assert.Equal(t, Kobo(1075), Naira(430).Percent(2.5))
+	assert.Equal(t, -1, Naira(1).Cmp(Naira(2)))
+	assert.True(t, Kobo(-1).IsNegative())
+	assert.Equal(t, 1500.5, Kobo(150050).Float64())
+	assert.Equal(t, Kobo(150050), AmountFromFloat(1500.5))
+
+	_, err = ParseAmount("100.001")
+	assert.NotNil(t, err)
+	_, err = ParseAmount("1,000")
+	assert.NotNil(t, err)
+	b, err := ParseAmount("100.000")
+	assert.Nil(t, err)
+	assert.Equal(t, Naira(100), b)
+
+	// Exponents are applied exactly, with the same precision check.
+	for str, want := range map[string]Amount{
+		"1e2": Naira(100), "1.5E3": Naira(1500), "-2.005e1": Kobo(-2005), "1234e-2": Kobo(1234),
+		"0.001e3": Naira(1), "5e-1": Kobo(50), "100.00E0": Naira(100),
+	} {
+		a, err := ParseAmount(str)
+		assert.Nil(t, err, str)
+		assert.Equal(t, want, a, str)
+	}
+	for _, str := range []string{"1.00005e2", "1e-3", "1e", "1e+", "e2", "1e2.5", "1e99", "9e18"} {
+		_, err = ParseAmount(str)
+		assert.NotNil(t, err, str)
+	}
+}
+
+func TestValidation_Params(t *testing.T) {
+	err := SingleTransferParam{
+		Amount:        0,
+		Reference:     "REF WITH SPACES",
+		BankCode:      "1",
+		AccountNumber: "12345",
+		Currency:      "USD",
+	}.Validate()
+	errs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	for _, field := range []string{"amount", "reference", "narration", "bankCode", "accountNumber", "currency"} {
+		_, found := errs.Field(field)
+		assert.True(t, found, field)
+	}
+
+	err = BulkTransferParam{
+		Title:                "TEST BATCH",
+		BatchReference:       "TEST_BCH_REF_INVALID",
+		Narration:            "TEST",
+		WalletId:             "TEST_WLT_ID",
+		OnValidationFailure:  ValidationFailedBreak,
+		NotificationInterval: 15,
+		TransactionList: []SingleTransferParam{
+			{Amount: Naira(100), Reference: "DUP", Narration: "TEST", BankCode: "101", AccountNumber: "3000017736", Currency: CurrencyNGN},
+			{Amount: Naira(100), Reference: "DUP", Narration: "TEST", BankCode: "101", AccountNumber: "123", Currency: CurrencyNGN},
+		},
+	}.Validate()
+	errs = err.(ValidationErrors)
+	assert.Len(t, errs, 3)
+	_, found := errs.Field("notificationInterval")
+	assert.True(t, found)
+	_, found = errs.Field("transactionList[1].accountNumber")
+	assert.True(t, found)
+	_, found = errs.Field("transactionList[1].reference")
+	assert.True(t, found)
+
+	err = ReserveAccountParam{
+		AccountReference: "TEST_ACCT_REF",
+		AccountName:      "Test Account",
+		CurrencyCode:     CurrencyNGN,
+		ContractCode:     "4934121686",
+		CustomerEmail:    "not-an-email",
+		CustomerName:     "John Doe",
+		IncomeSplitConfig: []IncomeSplitConfigParam{
+			{SubAccountCode: "MFY_SUB_1", SplitPercentage: 60},
+			{SubAccountCode: "MFY_SUB_2", SplitPercentage: 60},
+		},
+	}.Validate()
+	assert.Equal(t, "invalid params: customerEmail must be a valid email address; incomeSplitConfig split percentages add up to more than 100", err.Error())
+
+	assert.Nil(t, BVNDetailsParam{BVN: "22222222222", Name: "John Doe", DateOfBirth: "03-Oct-1993", MobileNo: "08142223149"}.Validate())
+	assert.NotNil(t, WalletBVNParam{BVN: "22222222222", DateOfBirth: "03-Oct-1993"}.Validate())
+}
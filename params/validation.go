@@ -0,0 +1,337 @@
+package params
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxReferenceLength is the longest reference Monnify accepts.
+	MaxReferenceLength = 255
+
+	bvnDetailsDateLayout = "02-Jan-2006"
+	walletBVNDateLayout  = "2006-01-02"
+)
+
+var (
+	referencePattern = regexp.MustCompile(`^[A-Za-z0-9_\-.|]+$`)
+	bankCodePattern  = regexp.MustCompile(`^[0-9]{3,6}$`)
+	emailPattern     = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+type (
+	// ValidationError describes why a single field is invalid. Field is the JSON name of the field, prefixed with its
+	// parents for nested params, e.g transactionList[2].accountNumber
+	ValidationError struct {
+		Field   string
+		Message string
+	}
+
+	// ValidationErrors is returned by Validate with every invalid field of the params.
+	ValidationErrors []ValidationError
+)
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%v %v", e.Field, e.Message)
+}
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ve := range e {
+		msgs = append(msgs, ve.Error())
+	}
+	return fmt.Sprintf("invalid params: %v", strings.Join(msgs, "; "))
+}
+
+// Field returns the error for field, if any.
+func (e ValidationErrors) Field(field string) (ValidationError, bool) {
+	for _, ve := range e {
+		if ve.Field == field {
+			return ve, true
+		}
+	}
+	return ValidationError{}, false
+}
+
+// Validate checks the reservation before it is sent.
+func (p ReserveAccountParam) Validate() error {
+	var v validator
+	v.reference("accountReference", p.AccountReference)
+	v.required("accountName", p.AccountName)
+	v.currency("currencyCode", p.CurrencyCode, true)
+	v.required("contractCode", p.ContractCode)
+	v.email("customerEmail", p.CustomerEmail)
+	v.required("customerName", p.CustomerName)
+
+	var split float64
+	for i, c := range p.IncomeSplitConfig {
+		v.nested(fmt.Sprintf("incomeSplitConfig[%d]", i), c.Validate())
+		split += c.SplitPercentage
+	}
+	if split > 100 {
+		v.add("incomeSplitConfig", "split percentages add up to more than 100")
+	}
+
+	v.nested("allowedPaymentSources", p.AllowedPaymentSources.Validate())
+	if p.RestrictPaymentSource && len(p.AllowedPaymentSources.BankAccounts) == 0 && len(p.AllowedPaymentSources.AccountNames) == 0 {
+		v.add("allowedPaymentSources", "is required when restrictPaymentSource is set")
+	}
+	return v.err()
+}
+
+// Validate checks a single income split.
+func (p IncomeSplitConfigParam) Validate() error {
+	var v validator
+	v.required("subAccountCode", p.SubAccountCode)
+	v.percentage("feePercentage", p.FeePercentage)
+	v.percentage("splitPercentage", p.SplitPercentage)
+	return v.err()
+}
+
+// Validate checks the allowed payment sources.
+func (p AllowedPaymentSourcesParam) Validate() error {
+	var v validator
+	for i, a := range p.BankAccounts {
+		v.nuban(fmt.Sprintf("bankAccounts[%d].accountNumber", i), a.AccountNumber)
+		v.bankCode(fmt.Sprintf("bankAccounts[%d].bankCode", i), a.BankCode)
+	}
+	for i, name := range p.AccountNames {
+		v.required(fmt.Sprintf("accountNames[%d]", i), name)
+	}
+	return v.err()
+}
+
+// Validate checks the transfer before it is sent.
+func (p SingleTransferParam) Validate() error {
+	var v validator
+	v.amount("amount", p.Amount)
+	v.reference("reference", p.Reference)
+	v.required("narration", p.Narration)
+	v.bankCode("bankCode", p.BankCode)
+	v.nuban("accountNumber", p.AccountNumber)
+	v.currency("currency", p.Currency, true)
+	return v.err()
+}
+
+// Validate checks the batch and every transfer in it before it is sent.
+func (p BulkTransferParam) Validate() error {
+	var v validator
+	v.required("title", p.Title)
+	v.reference("batchReference", p.BatchReference)
+	v.required("narration", p.Narration)
+	v.required("walletId", p.WalletId)
+
+	switch p.OnValidationFailure {
+	case ValidationFailedContinue, ValidationFailedBreak:
+	default:
+		v.add("onValidationFailure", fmt.Sprintf("must be %v or %v", ValidationFailedContinue, ValidationFailedBreak))
+	}
+
+	switch p.NotificationInterval {
+	case NotificationInterval10, NotificationInterval20, NotificationInterval50, NotificationInterval100:
+	default:
+		v.add("notificationInterval", "must be 10, 20, 50 or 100")
+	}
+
+	if len(p.TransactionList) == 0 {
+		v.add("transactionList", "is required")
+	}
+	seen := map[string]bool{}
+	for i, t := range p.TransactionList {
+		field := fmt.Sprintf("transactionList[%d]", i)
+		v.nested(field, t.Validate())
+		if t.Reference != "" && seen[t.Reference] {
+			v.add(field+".reference", "is duplicated in the batch")
+		}
+		seen[t.Reference] = true
+	}
+	return v.err()
+}
+
+// Validate checks the BVN and account details.
+func (p BVNAccountMatchParam) Validate() error {
+	var v validator
+	v.bvn("bvn", p.BVN)
+	v.nuban("accountNumber", p.AccountNumber)
+	v.bankCode("bankCode", p.BankCode)
+	return v.err()
+}
+
+// Validate checks the customer details.
+func (p BVNDetailsParam) Validate() error {
+	var v validator
+	v.bvn("bvn", p.BVN)
+	v.required("name", p.Name)
+	v.date("dateOfBirth", p.DateOfBirth, bvnDetailsDateLayout)
+	v.digits("mobileNo", p.MobileNo, 11)
+	return v.err()
+}
+
+// Validate checks the wallet owner details.
+func (p WalletCustomerParam) Validate() error {
+	var v validator
+	v.required("customerName", p.Name)
+	v.email("customerEmail", p.Email)
+	v.nested("bvnDetails", p.BVNDetails.Validate())
+	return v.err()
+}
+
+// Validate checks the BVN of the wallet owner.
+func (p WalletBVNParam) Validate() error {
+	var v validator
+	v.bvn("bvn", p.BVN)
+	v.date("bvnDateOfBirth", p.DateOfBirth, walletBVNDateLayout)
+	return v.err()
+}
+
+// Validate checks the transaction before it is initialized. ContractCode and CurrencyCode are defaulted by the client
+// before this is called.
+func (p InitializeTransactionParam) Validate() error {
+	var v validator
+	v.amount("amount", p.Amount)
+	v.required("customerName", p.CustomerName)
+	v.email("customerEmail", p.CustomerEmail)
+	v.reference("paymentReference", p.PaymentReference)
+	v.required("paymentDescription", p.PaymentDescription)
+	v.currency("currencyCode", p.CurrencyCode, true)
+	v.required("contractCode", p.ContractCode)
+
+	if p.RedirectUrl != "" {
+		if u, err := url.ParseRequestURI(p.RedirectUrl); err != nil || u.Host == "" {
+			v.add("redirectUrl", "must be an absolute url")
+		}
+	}
+	for i, m := range p.PaymentMethods {
+		if m != PaymentMethodCard && m != PaymentMethodAccountTransfer {
+			v.add(fmt.Sprintf("paymentMethods[%d]", i), fmt.Sprintf("must be %v or %v", PaymentMethodCard, PaymentMethodAccountTransfer))
+		}
+	}
+	return v.err()
+}
+
+// validator accumulates the errors of the fields checked.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field, message string) {
+	v.errs = append(v.errs, ValidationError{Field: field, Message: message})
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// nested adds the errors of a nested params, prefixing their field with the field of the parent.
+func (v *validator) nested(parent string, err error) {
+	if err == nil {
+		return
+	}
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		v.add(parent, err.Error())
+		return
+	}
+	for _, ve := range errs {
+		v.add(parent+"."+ve.Field, ve.Message)
+	}
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+		return false
+	}
+	return true
+}
+
+func (v *validator) reference(field, value string) {
+	if !v.required(field, value) {
+		return
+	}
+	if len(value) > MaxReferenceLength {
+		v.add(field, fmt.Sprintf("must not be longer than %v characters", MaxReferenceLength))
+	}
+	if !referencePattern.MatchString(value) {
+		v.add(field, "may only contain letters, digits and - _ . |")
+	}
+}
+
+func (v *validator) amount(field string, value Amount) {
+	if value <= 0 {
+		v.add(field, "must be greater than zero")
+	}
+}
+
+func (v *validator) currency(field string, value Currency, required bool) {
+	switch {
+	case value == "" && !required:
+	case value == "":
+		v.add(field, "is required")
+	case value != CurrencyNGN:
+		v.add(field, fmt.Sprintf("%v is not supported", value))
+	}
+}
+
+func (v *validator) nuban(field, value string) {
+	if v.required(field, value) && !isDigits(value, 10) {
+		v.add(field, "must be a 10 digit NUBAN")
+	}
+}
+
+func (v *validator) bankCode(field, value string) {
+	if v.required(field, value) && !bankCodePattern.MatchString(value) {
+		v.add(field, "must be a 3 to 6 digit bank code")
+	}
+}
+
+func (v *validator) bvn(field, value string) {
+	if v.required(field, value) && !isDigits(value, 11) {
+		v.add(field, "must be 11 digits")
+	}
+}
+
+func (v *validator) digits(field, value string, n int) {
+	if v.required(field, value) && !isDigits(value, n) {
+		v.add(field, fmt.Sprintf("must be %v digits", n))
+	}
+}
+
+func (v *validator) email(field, value string) {
+	if v.required(field, value) && !emailPattern.MatchString(value) {
+		v.add(field, "must be a valid email address")
+	}
+}
+
+func (v *validator) date(field, value, layout string) {
+	if !v.required(field, value) {
+		return
+	}
+	if _, err := time.Parse(layout, value); err != nil {
+		v.add(field, fmt.Sprintf("must be formatted as %v", layout))
+	}
+}
+
+func (v *validator) percentage(field string, value float64) {
+	if value < 0 || value > 100 {
+		v.add(field, "must be between 0 and 100")
+	}
+}
+
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
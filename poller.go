@@ -0,0 +1,146 @@
+package gomonnify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrPollAttemptsExhausted is returned by AwaitFinalStatus and AwaitBatchFinalStatus when PollPolicy.MaxAttempts polls
+// were sent without the transfer reaching a final status. The last details fetched are returned with it.
+var ErrPollAttemptsExhausted = errors.New("transfer did not reach a final status within the allowed poll attempts")
+
+// DefaultPollPolicy is used for every PollPolicy field left at its zero value.
+var DefaultPollPolicy = PollPolicy{
+	InitialInterval: 2 * time.Second,
+	MaxInterval:     30 * time.Second,
+	Multiplier:      2,
+}
+
+// PollPolicy controls how often the status of a transfer is polled. The first poll is sent straight away, the second
+// after InitialInterval and every following interval is multiplied by Multiplier, up to MaxInterval.
+// MaxAttempts bounds the number of polls, 0 polls until the context is done.
+type PollPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	MaxAttempts     int
+}
+
+// AwaitFinalStatus polls the single transfer until its status is SUCCESS, FAILED, REVERSED or EXPIRED and returns its
+// details. Network errors and 5xx or 429 responses are retried with the next poll, any other error is returned as is.
+// If ctx is done or the attempts are exhausted first, the last details fetched (if any) are returned with the error.
+func (d *disbursements) AwaitFinalStatus(ctx context.Context, reference string, policy PollPolicy) (*SingleTransferDetails, error) {
+	if reference == "" {
+		return nil, errors.New("reference is required")
+	}
+
+	var last *SingleTransferDetails
+	err := poll(ctx, policy, func() (bool, error) {
+		result, err := do[SingleTransferDetailsResponse](d.base, call{
+			ctx:    ctx,
+			method: http.MethodGet,
+			path:   "/v1/disbursements/single/summary",
+			query:  url.Values{"reference": {reference}},
+			auth:   requestAuthTypeBasic,
+		})
+		if err != nil {
+			return false, err
+		}
+		last = &result.ResponseBody
+		return last.Status.IsFinal(), nil
+	})
+	return last, err
+}
+
+// AwaitBatchFinalStatus polls the bulk transfer until its batch status is COMPLETED, FAILED or EXPIRED and returns its
+// details. Errors are handled as in AwaitFinalStatus.
+func (d *disbursements) AwaitBatchFinalStatus(ctx context.Context, batchReference string, policy PollPolicy) (*BulkTransferDetails, error) {
+	if batchReference == "" {
+		return nil, errors.New("batchReference is required")
+	}
+
+	var last *BulkTransferDetails
+	err := poll(ctx, policy, func() (bool, error) {
+		result, err := do[BulkTransferDetailsResponse](d.base, call{
+			ctx:    ctx,
+			method: http.MethodGet,
+			path:   "/v1/disbursements/batch/summary",
+			query:  url.Values{"reference": {batchReference}},
+			auth:   requestAuthTypeBasic,
+		})
+		if err != nil {
+			return false, err
+		}
+		last = &result.ResponseBody
+		return last.BatchStatus.IsFinal(), nil
+	})
+	return last, err
+}
+
+// poll calls check until it reports done, returns an error that is not worth retrying, ctx is done or the policy
+// runs out of attempts.
+func poll(ctx context.Context, policy PollPolicy, check func() (bool, error)) error {
+	policy = policy.withDefaults()
+	interval := policy.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		done, err := check()
+		if done {
+			return nil
+		}
+		if err != nil && !retryable(err) {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			if err != nil {
+				return err
+			}
+			return ErrPollAttemptsExhausted
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * policy.Multiplier)
+		if interval > policy.MaxInterval {
+			interval = policy.MaxInterval
+		}
+	}
+}
+
+func (p PollPolicy) withDefaults() PollPolicy {
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = DefaultPollPolicy.InitialInterval
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = DefaultPollPolicy.MaxInterval
+	}
+	if p.MaxInterval < p.InitialInterval {
+		p.MaxInterval = p.InitialInterval
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultPollPolicy.Multiplier
+	}
+	return p
+}
+
+// retryable reports whether a failed poll is worth sending again: anything that is not a Monnify rejection, e.g.
+// network errors, and rejections caused by server errors or rate limiting.
+func retryable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	return apiErr.HTTPStatus >= http.StatusInternalServerError || apiErr.HTTPStatus == http.StatusTooManyRequests
+}
@@ -0,0 +1,344 @@
+// Package references generates the unique references Monnify requires on transfers, batches, reserved accounts and
+// transactions, and parses them back.
+//
+// A reference is an optional prefix (e.g. a tenant code) and a body, separated by a dash: PAYROLL-01HF3Q4N6Y8V2C7T5X9R0B1K3M.
+// The body is one of three formats:
+//   - FormatULID: a 26 character ULID, sortable by creation time.
+//   - FormatTimestamp: the UTC creation time to the millisecond and a random part, e.g. 20261019153045123.7KQ2M9XR4T
+//   - FormatSequence: a zero padded counter, e.g. 000042
+//
+// Generated references only contain letters, digits and the characters - _ . accepted by Monnify, and never exceed
+// Config.MaxLength.
+//
+// FormatSequence references are only unique if their counter is. By default the counter is kept in memory and starts
+// again from Config.SequenceStart whenever a Generator is created, so two processes, or one process after a restart,
+// produce the same references. Outside of tests, set Config.Sequence to a SequenceSource backed by shared, persistent
+// storage, e.g. a database sequence.
+package references
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FormatULID      Format = "ulid"
+	FormatTimestamp Format = "timestamp"
+	FormatSequence  Format = "sequence"
+
+	// Separator is placed between the prefix and the body of a reference.
+	Separator = "-"
+
+	DefaultRandomLength  = 10
+	DefaultSequenceWidth = 6
+
+	ulidLength      = 26
+	timestampLayout = "20060102150405"
+	timestampDigits = len(timestampLayout) + 3
+	maxSequenceLen  = 20
+	crockford       = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+var prefixPattern = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+type (
+	// Format is the format of the body of a reference.
+	Format string
+
+	// Config is used to create a Generator.
+	// Format - the format of the generated references. Required.
+	// Prefix - optional, e.g. a tenant or product code. Letters, digits, underscore and dot only.
+	// MaxLength - the longest reference allowed. Defaults to params.MaxReferenceLength, New fails if the references
+	// generated could be longer.
+	// RandomLength - the length of the random part of FormatTimestamp references. Defaults to DefaultRandomLength.
+	// SequenceStart - the first value of FormatSequence references counted in memory. Defaults to 1.
+	// SequenceWidth - the width FormatSequence values are zero padded to. Defaults to DefaultSequenceWidth.
+	// Sequence - the source of FormatSequence values. Defaults to an in-memory counter starting at SequenceStart, which
+	// is not shared between processes nor persisted, see the package documentation.
+	// Clock - returns the current time. Defaults to time.Now.
+	// Random - source of randomness. Defaults to crypto/rand.Reader.
+	Config struct {
+		Format        Format
+		Prefix        string
+		MaxLength     int
+		RandomLength  int
+		SequenceStart uint64
+		SequenceWidth int
+		Sequence      SequenceSource
+		Clock         func() time.Time
+		Random        io.Reader
+	}
+
+	// SequenceSource hands out the values of FormatSequence references. Next must never return the same value twice,
+	// including across processes and restarts, and must be safe for concurrent use.
+	SequenceSource interface {
+		Next() (uint64, error)
+	}
+
+	// SequenceSourceFunc adapts a function to a SequenceSource.
+	SequenceSourceFunc func() (uint64, error)
+
+	// Generator generates references. It is safe for concurrent use.
+	Generator struct {
+		config Config
+
+		mu       sync.Mutex
+		sequence uint64
+		lastMs   uint64
+		lastRand [10]byte
+	}
+
+	// Info is what Parse extracts from a reference.
+	// Time is the creation time for FormatULID and FormatTimestamp references, in UTC. Sequence is only set for
+	// FormatSequence references.
+	Info struct {
+		Format   Format
+		Prefix   string
+		Time     time.Time
+		Sequence uint64
+	}
+)
+
+// New returns a Generator for config.
+func New(config Config) (*Generator, error) {
+	if config.Prefix != "" && !prefixPattern.MatchString(config.Prefix) {
+		return nil, errors.New("malformed config - prefix may only contain letters, digits, _ and .")
+	}
+	if config.MaxLength <= 0 {
+		config.MaxLength = params.MaxReferenceLength
+	}
+	if config.RandomLength <= 0 {
+		config.RandomLength = DefaultRandomLength
+	}
+	if config.SequenceStart == 0 {
+		config.SequenceStart = 1
+	}
+	if config.SequenceWidth <= 0 {
+		config.SequenceWidth = DefaultSequenceWidth
+	}
+	if config.SequenceWidth > maxSequenceLen {
+		return nil, fmt.Errorf("malformed config - sequence width must not be more than %v", maxSequenceLen)
+	}
+	if config.Clock == nil {
+		config.Clock = time.Now
+	}
+	if config.Random == nil {
+		config.Random = rand.Reader
+	}
+
+	var bodyLength int
+	switch config.Format {
+	case FormatULID:
+		bodyLength = ulidLength
+	case FormatTimestamp:
+		bodyLength = timestampDigits + 1 + config.RandomLength
+	case FormatSequence:
+		bodyLength = maxSequenceLen
+	default:
+		return nil, fmt.Errorf("malformed config - format must be %v, %v or %v", FormatULID, FormatTimestamp, FormatSequence)
+	}
+	if l := len(withPrefix(config.Prefix, strings.Repeat("0", bodyLength))); l > config.MaxLength {
+		return nil, fmt.Errorf("malformed config - references could be %v characters long, more than the %v allowed", l, config.MaxLength)
+	}
+
+	return &Generator{config: config, sequence: config.SequenceStart}, nil
+}
+
+// Next returns a new reference.
+func (g *Generator) Next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var body string
+	switch g.config.Format {
+	case FormatULID:
+		b, err := g.nextULID()
+		if err != nil {
+			return "", err
+		}
+		body = b
+	case FormatTimestamp:
+		random := make([]byte, g.config.RandomLength)
+		if _, err := io.ReadFull(g.config.Random, random); err != nil {
+			return "", err
+		}
+		for i, c := range random {
+			random[i] = crockford[c%32]
+		}
+		now := g.config.Clock().UTC()
+		body = fmt.Sprintf("%v%03d.%s", now.Format(timestampLayout), now.Nanosecond()/int(time.Millisecond), random)
+	case FormatSequence:
+		n, err := g.nextSequence()
+		if err != nil {
+			return "", err
+		}
+		body = fmt.Sprintf("%0*d", g.config.SequenceWidth, n)
+	}
+	return withPrefix(g.config.Prefix, body), nil
+}
+
+// nextSequence returns the next value of the Sequence source, or of the in-memory counter.
+func (g *Generator) nextSequence() (uint64, error) {
+	if g.config.Sequence != nil {
+		return g.config.Sequence.Next()
+	}
+	if g.sequence == 0 {
+		return 0, errors.New("reference sequence exhausted")
+	}
+	n := g.sequence
+	g.sequence++
+	return n, nil
+}
+
+// nextULID returns a ULID for the current time. ULIDs generated within the same millisecond increment the random
+// part of the previous one, so they remain unique and sorted.
+func (g *Generator) nextULID() (string, error) {
+	ms := uint64(g.config.Clock().UnixNano() / int64(time.Millisecond))
+	if ms == g.lastMs {
+		i := len(g.lastRand) - 1
+		for ; i >= 0; i-- {
+			g.lastRand[i]++
+			if g.lastRand[i] != 0 {
+				break
+			}
+		}
+		if i < 0 {
+			return "", errors.New("too many references generated within the same millisecond")
+		}
+	} else {
+		if _, err := io.ReadFull(g.config.Random, g.lastRand[:]); err != nil {
+			return "", err
+		}
+		g.lastMs = ms
+	}
+
+	var id [16]byte
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> (8 * (5 - i)))
+	}
+	copy(id[6:], g.lastRand[:])
+	return encodeULID(id), nil
+}
+
+func (f SequenceSourceFunc) Next() (uint64, error) {
+	return f()
+}
+
+// Parse extracts the prefix and the metadata embedded in a reference generated by a Generator.
+func Parse(reference string) (Info, error) {
+	var info Info
+	body := reference
+	if i := strings.Index(reference, Separator); i >= 0 {
+		info.Prefix, body = reference[:i], reference[i+1:]
+		if !prefixPattern.MatchString(info.Prefix) {
+			return Info{}, fmt.Errorf("reference %v has an invalid prefix", reference)
+		}
+	}
+
+	switch {
+	case strings.Contains(body, "."):
+		parts := strings.SplitN(body, ".", 2)
+		if len(parts[0]) != timestampDigits || !isCrockford(parts[1]) {
+			break
+		}
+		t, err := time.Parse(timestampLayout, parts[0][:len(timestampLayout)])
+		if err != nil {
+			break
+		}
+		ms, err := strconv.Atoi(parts[0][len(timestampLayout):])
+		if err != nil {
+			break
+		}
+		info.Format = FormatTimestamp
+		info.Time = t.Add(time.Duration(ms) * time.Millisecond)
+		return info, nil
+	case len(body) == ulidLength:
+		id, ok := decodeULID(body)
+		if !ok {
+			break
+		}
+		var ms uint64
+		for i := 0; i < 6; i++ {
+			ms = ms<<8 | uint64(id[i])
+		}
+		info.Format = FormatULID
+		info.Time = time.Unix(0, int64(ms)*int64(time.Millisecond)).UTC()
+		return info, nil
+	case len(body) > 0 && len(body) <= maxSequenceLen:
+		n, err := strconv.ParseUint(body, 10, 64)
+		if err != nil {
+			break
+		}
+		info.Format = FormatSequence
+		info.Sequence = n
+		return info, nil
+	}
+	return Info{}, fmt.Errorf("reference %v was not generated by a references.Generator", reference)
+}
+
+func withPrefix(prefix, body string) string {
+	if prefix == "" {
+		return body
+	}
+	return prefix + Separator + body
+}
+
+// encodeULID encodes the 128 bits of id as 26 Crockford base32 characters, most significant first.
+func encodeULID(id [16]byte) string {
+	var hi, lo uint64
+	for i := 0; i < 8; i++ {
+		hi = hi<<8 | uint64(id[i])
+		lo = lo<<8 | uint64(id[i+8])
+	}
+
+	out := make([]byte, ulidLength)
+	for i := ulidLength - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
+
+func decodeULID(s string) ([16]byte, bool) {
+	var id [16]byte
+	// The first character only holds the top 3 bits of the 128.
+	if len(s) != ulidLength || strings.IndexByte(crockford[:8], s[0]) < 0 {
+		return id, false
+	}
+
+	var hi, lo uint64
+	for i := 0; i < ulidLength; i++ {
+		v := strings.IndexByte(crockford, s[i])
+		if v < 0 {
+			return id, false
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	for i := 0; i < 8; i++ {
+		id[7-i] = byte(hi >> (8 * i))
+		id[15-i] = byte(lo >> (8 * i))
+	}
+	return id, true
+}
+
+func isCrockford(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(crockford, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
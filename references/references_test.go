@@ -0,0 +1,115 @@
+package references
+
+import (
+	"errors"
+	"github.com/jcobhams/gomonnify/params"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReferences_Generate(t *testing.T) {
+	_, err := New(Config{Format: FormatULID, Prefix: "BAD-PREFIX"})
+	assert.NotNil(t, err)
+	_, err = New(Config{Format: "uuid"})
+	assert.NotNil(t, err)
+	_, err = New(Config{Format: FormatTimestamp, Prefix: "TENANT", MaxLength: 20})
+	assert.NotNil(t, err)
+
+	ulids, err := New(Config{Format: FormatULID, Prefix: "PAYROLL"})
+	assert.Nil(t, err)
+	seen := map[string]bool{}
+	var last string
+	for i := 0; i < 1000; i++ {
+		ref, err := ulids.Next()
+		assert.Nil(t, err)
+		assert.Len(t, ref, len("PAYROLL-")+26)
+		assert.False(t, seen[ref])
+		assert.True(t, ref > last)
+		seen[ref], last = true, ref
+	}
+
+	createdAt := time.Date(2026, 10, 19, 15, 30, 45, 123000000, time.UTC)
+	timestamps, err := New(Config{
+		Format: FormatTimestamp,
+		Prefix: "ACME",
+		Clock:  func() time.Time { return createdAt },
+		Random: strings.NewReader(strings.Repeat("\x00\x01\x1f", 4)),
+	})
+	assert.Nil(t, err)
+	ref, err := timestamps.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "ACME-20261019153045123.01Z01Z01Z0", ref)
+
+	sequence, err := New(Config{Format: FormatSequence, Prefix: "INV", SequenceStart: 41})
+	assert.Nil(t, err)
+	ref, _ = sequence.Next()
+	assert.Equal(t, "INV-000041", ref)
+	ref, _ = sequence.Next()
+	assert.Equal(t, "INV-000042", ref)
+
+	// A shared counter keeps sequences unique across generators, e.g. after a restart.
+	var counter uint64 = 99
+	source := SequenceSourceFunc(func() (uint64, error) {
+		counter++
+		return counter, nil
+	})
+	for _, want := range []string{"INV-000100", "INV-000101"} {
+		restarted, err := New(Config{Format: FormatSequence, Prefix: "INV", Sequence: source})
+		assert.Nil(t, err)
+		ref, err = restarted.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, want, ref)
+	}
+	failing, _ := New(Config{Format: FormatSequence, Sequence: SequenceSourceFunc(func() (uint64, error) {
+		return 0, errors.New("sequence unavailable")
+	})})
+	_, err = failing.Next()
+	assert.NotNil(t, err)
+
+	timestamps, err = New(Config{Format: FormatTimestamp, Prefix: "ACME"})
+	assert.Nil(t, err)
+	for _, g := range []*Generator{ulids, timestamps, sequence} {
+		ref, err := g.Next()
+		assert.Nil(t, err)
+		transfer := params.SingleTransferParam{
+			Amount:        params.Naira(100),
+			Reference:     ref,
+			Narration:     "TEST",
+			BankCode:      "101",
+			AccountNumber: "3000017736",
+			Currency:      params.CurrencyNGN,
+		}
+		assert.Nil(t, transfer.Validate())
+	}
+}
+
+func TestReferences_Parse(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 15, 30, 45, 123000000, time.UTC)
+	ulids, _ := New(Config{Format: FormatULID, Prefix: "tenant_1", Clock: func() time.Time { return createdAt }})
+	ref, err := ulids.Next()
+	assert.Nil(t, err)
+	info, err := Parse(ref)
+	assert.Nil(t, err)
+	assert.Equal(t, FormatULID, info.Format)
+	assert.Equal(t, "tenant_1", info.Prefix)
+	assert.Equal(t, createdAt, info.Time)
+
+	info, err = Parse("ACME-20261019153045123.01Z01Z01Z0")
+	assert.Nil(t, err)
+	assert.Equal(t, FormatTimestamp, info.Format)
+	assert.Equal(t, "ACME", info.Prefix)
+	assert.Equal(t, createdAt, info.Time)
+
+	info, err = Parse("000042")
+	assert.Nil(t, err)
+	assert.Equal(t, FormatSequence, info.Format)
+	assert.Equal(t, "", info.Prefix)
+	assert.Equal(t, uint64(42), info.Sequence)
+
+	for _, ref := range []string{"", "ACME-", "ACME-2026.XYZ", "MNFY|TEST", "ACME-20261319153045123.01Z", "ACME-U1Z01Z01Z01Z01Z01Z01Z01Z"} {
+		_, err := Parse(ref)
+		assert.NotNil(t, err, ref)
+	}
+}
@@ -2,32 +2,25 @@ package gomonnify
 
 import (
 	"errors"
-	"fmt"
 	"github.com/jcobhams/gomonnify/params"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
 )
 
 //ReserveAccount reserves an account number for a customer based on the provided configuration params.
 //Docs: https://docs.teamapt.com/display/MON/Reserving+An+Account
 func (r *reservedAccounts) ReserveAccount(params params.ReserveAccountParam) (*ReserveAccountResponse, error) {
-	url := fmt.Sprintf("%v/v1/bank-transfer/reserved-accounts", r.APIBaseUrl)
-	rawResponse, statusCode, err := r.postRequest(url, requestAuthTypeBearer, params)
-	if err != nil {
+	if err := r.validate(params); err != nil {
 		return nil, err
 	}
 
-	result := ReserveAccountResponse{}
-	err = r.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[ReserveAccountResponse](r.base, call{
+		method: http.MethodPost,
+		path:   "/v1/bank-transfer/reserved-accounts",
+		auth:   requestAuthTypeBearer,
+		body:   params,
+	})
 }
 
 //Details gets the reserved account information for the provided account reference.
@@ -37,23 +30,11 @@ func (r *reservedAccounts) Details(accountReference string) (*ReserveAccountResp
 		return nil, errors.New("accountReference is required")
 	}
 
-	url := fmt.Sprintf("%v/v1/bank-transfer/reserved-accounts/%v", r.APIBaseUrl, accountReference)
-	rawResponse, statusCode, err := r.getRequest(url, requestAuthTypeBearer)
-	if err != nil {
-		return nil, err
-	}
-
-	var result ReserveAccountResponse
-	err = r.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[ReserveAccountResponse](r.base, call{
+		method: http.MethodGet,
+		path:   urlPath("/v1/bank-transfer/reserved-accounts/%v", accountReference),
+		auth:   requestAuthTypeBearer,
+	})
 }
 
 //Deallocate deletes a reserved account.
@@ -63,23 +44,12 @@ func (r *reservedAccounts) Deallocate(accountNumber string) error {
 		return errors.New("accountNumber is required")
 	}
 
-	url := fmt.Sprintf("%v/v1/bank-transfer/reserved-accounts/%v", r.APIBaseUrl, accountNumber)
-	rawResponse, statusCode, err := r.deleteRequest(url, requestAuthTypeBearer)
-	if err != nil {
-		return err
-	}
-
-	var result ReserveAccountResponse
-	err = r.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return err
-	}
-
-	if statusCode != http.StatusOK {
-		return failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return nil
+	_, err := do[ReserveAccountResponse](r.base, call{
+		method: http.MethodDelete,
+		path:   urlPath("/v1/bank-transfer/reserved-accounts/%v", accountNumber),
+		auth:   requestAuthTypeBearer,
+	})
+	return err
 }
 
 //Transactions fetches all the transaction on a reserved account for the provided account reference.
@@ -89,23 +59,16 @@ func (r *reservedAccounts) Transactions(accountReference string, page, size int)
 		return nil, errors.New("accountNumber is required")
 	}
 
-	url := fmt.Sprintf("%v/v1/bank-transfer/reserved-accounts/transactions?accountReference=%v&page=%v&size=%v", r.APIBaseUrl, accountReference, page, size)
-	rawResponse, statusCode, err := r.getRequest(url, requestAuthTypeBearer)
-	if err != nil {
-		return nil, err
-	}
-
-	var result ReservedAccountTransactionsResponse
-	err = r.unmarshallJson(strings.NewReader(rawResponse), &result)
-	if err != nil {
-		return nil, err
-	}
-
-	if statusCode != http.StatusOK {
-		return nil, failedRequestMessage(statusCode, result.ResponseCode, result.ResponseMessage)
-	}
-
-	return &result, nil
+	return do[ReservedAccountTransactionsResponse](r.base, call{
+		method: http.MethodGet,
+		path:   "/v1/bank-transfer/reserved-accounts/transactions",
+		query: url.Values{
+			"accountReference": {accountReference},
+			"page":             {strconv.Itoa(page)},
+			"size":             {strconv.Itoa(size)},
+		},
+		auth: requestAuthTypeBearer,
+	})
 }
 
 //TODO
@@ -0,0 +1,153 @@
+package gomonnify
+
+import "fmt"
+
+// StatusTransitionError is returned by ValidateTransition when a transfer or batch cannot move from one status to the
+// other, e.g. a webhook reporting PENDING for a transfer already recorded as SUCCESS.
+type StatusTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("invalid status transition from %v to %v", e.From, e.To)
+}
+
+// transferTransitions lists the statuses a transfer can move to from each status. Moves between the processing
+// statuses are allowed in any order, since Monnify does not always report them in sequence.
+var transferTransitions = map[TransferStatus][]TransferStatus{
+	TransferStatusPendingAuthorization: {
+		TransferStatusOTPEmailDispatchFailed, TransferStatusAwaitingProcessing, TransferStatusPending,
+		TransferStatusInProgress, TransferStatusSuccess, TransferStatusFailed, TransferStatusExpired,
+	},
+	TransferStatusOTPEmailDispatchFailed: {TransferStatusPendingAuthorization, TransferStatusFailed, TransferStatusExpired},
+	TransferStatusAwaitingProcessing: {
+		TransferStatusPending, TransferStatusInProgress, TransferStatusSuccess, TransferStatusFailed,
+	},
+	TransferStatusPending: {
+		TransferStatusAwaitingProcessing, TransferStatusInProgress, TransferStatusSuccess, TransferStatusFailed,
+	},
+	TransferStatusInProgress: {
+		TransferStatusAwaitingProcessing, TransferStatusPending, TransferStatusSuccess, TransferStatusFailed,
+	},
+	TransferStatusSuccess:  {TransferStatusReversed},
+	TransferStatusFailed:   nil,
+	TransferStatusReversed: nil,
+	TransferStatusExpired:  nil,
+}
+
+// batchTransitions lists the statuses a batch can move to from each status.
+var batchTransitions = map[BatchStatus][]BatchStatus{
+	BatchStatusPendingAuthorization: {
+		BatchStatusOTPEmailDispatchFailed, BatchStatusAwaitingProcessing, BatchStatusInProgress,
+		BatchStatusCompleted, BatchStatusFailed, BatchStatusExpired,
+	},
+	BatchStatusOTPEmailDispatchFailed: {BatchStatusPendingAuthorization, BatchStatusFailed, BatchStatusExpired},
+	BatchStatusAwaitingProcessing:     {BatchStatusInProgress, BatchStatusCompleted, BatchStatusFailed},
+	BatchStatusInProgress:             {BatchStatusCompleted, BatchStatusFailed},
+	BatchStatusCompleted:              nil,
+	BatchStatusFailed:                 nil,
+	BatchStatusExpired:                nil,
+}
+
+// IsKnown reports whether s is one of the documented transfer statuses.
+func (s TransferStatus) IsKnown() bool {
+	_, ok := transferTransitions[s]
+	return ok
+}
+
+// IsFinal reports whether the transfer is done processing: SUCCESS, FAILED, REVERSED or EXPIRED.
+// Note that a successful transfer can still be reversed later on.
+func (s TransferStatus) IsFinal() bool {
+	switch s {
+	case TransferStatusSuccess, TransferStatusFailed, TransferStatusReversed, TransferStatusExpired:
+		return true
+	}
+	return false
+}
+
+// IsSuccessful reports whether the money reached the recipient.
+func (s TransferStatus) IsSuccessful() bool {
+	return s == TransferStatusSuccess
+}
+
+// RequiresAuthorization reports whether the transfer is waiting for its OTP, see AuthorizeSingleTransfer and ResendOTP.
+func (s TransferStatus) RequiresAuthorization() bool {
+	return s == TransferStatusPendingAuthorization || s == TransferStatusOTPEmailDispatchFailed
+}
+
+// CanTransitionTo reports whether a transfer in status s can be reported next in status next.
+// Reporting the same status again is always allowed, and any known status is allowed when s is empty (not yet known).
+func (s TransferStatus) CanTransitionTo(next TransferStatus) bool {
+	if !next.IsKnown() {
+		return false
+	}
+	if s == "" || s == next {
+		return true
+	}
+	for _, to := range transferTransitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns a *StatusTransitionError if a transfer cannot move from s to next.
+func (s TransferStatus) ValidateTransition(next TransferStatus) error {
+	if !s.CanTransitionTo(next) {
+		return &StatusTransitionError{From: string(s), To: string(next)}
+	}
+	return nil
+}
+
+// IsKnown reports whether s is one of the documented batch statuses.
+func (s BatchStatus) IsKnown() bool {
+	_, ok := batchTransitions[s]
+	return ok
+}
+
+// IsFinal reports whether the batch is done processing: COMPLETED, FAILED or EXPIRED.
+func (s BatchStatus) IsFinal() bool {
+	switch s {
+	case BatchStatusCompleted, BatchStatusFailed, BatchStatusExpired:
+		return true
+	}
+	return false
+}
+
+// IsSuccessful reports whether the batch was processed. Transfers in a completed batch can still have failed
+// individually, see BulkTransferTransactions.
+func (s BatchStatus) IsSuccessful() bool {
+	return s == BatchStatusCompleted
+}
+
+// RequiresAuthorization reports whether the batch is waiting for its OTP, see AuthorizeBulkTransfer.
+func (s BatchStatus) RequiresAuthorization() bool {
+	return s == BatchStatusPendingAuthorization || s == BatchStatusOTPEmailDispatchFailed
+}
+
+// CanTransitionTo reports whether a batch in status s can be reported next in status next.
+// Reporting the same status again is always allowed, and any known status is allowed when s is empty (not yet known).
+func (s BatchStatus) CanTransitionTo(next BatchStatus) bool {
+	if !next.IsKnown() {
+		return false
+	}
+	if s == "" || s == next {
+		return true
+	}
+	for _, to := range batchTransitions[s] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns a *StatusTransitionError if a batch cannot move from s to next.
+func (s BatchStatus) ValidateTransition(next BatchStatus) error {
+	if !s.CanTransitionTo(next) {
+		return &StatusTransitionError{From: string(s), To: string(next)}
+	}
+	return nil
+}
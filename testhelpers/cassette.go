@@ -0,0 +1,229 @@
+package testhelpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// RecorderModeRecord sends every request upstream and records the interactions to the cassette.
+	RecorderModeRecord RecorderMode = "record"
+	// RecorderModeReplay serves every request from the cassette and never touches the network.
+	RecorderModeReplay RecorderMode = "replay"
+	// RecorderModeAuto replays when the cassette file exists and records otherwise.
+	RecorderModeAuto RecorderMode = "auto"
+
+	// Redacted replaces credentials and secrets in recorded cassettes.
+	Redacted string = "REDACTED"
+)
+
+var redactedBodyFields = regexp.MustCompile(`("(?:accessToken|secretKey|apiKey|clientSecret)"\s*:\s*)"[^"]*"`)
+
+type (
+	RecorderMode string
+
+	// Cassette is the on-disk (JSON) format of recorded interactions.
+	Cassette struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+
+	Interaction struct {
+		Request  RecordedHTTPRequest  `json:"request"`
+		Response RecordedHTTPResponse `json:"response"`
+	}
+
+	RecordedHTTPRequest struct {
+		Method string      `json:"method"`
+		URL    string      `json:"url"`
+		Header http.Header `json:"header"`
+		Body   string      `json:"body"`
+	}
+
+	RecordedHTTPResponse struct {
+		StatusCode int         `json:"statusCode"`
+		Header     http.Header `json:"header"`
+		Body       string      `json:"body"`
+	}
+
+	// Recorder is an http.RoundTripper that records real (e.g sandbox) Monnify traffic to a cassette file and replays
+	// it deterministically, so tests can run against captured responses instead of hand written fixtures.
+	// Plug it into the client through Config.Transport and call Stop when done to save the cassette.
+	//
+	// Authorization headers, login access tokens and any value passed to Redact are replaced with REDACTED
+	// before anything is written to disk. Interactions are replayed in the order they were recorded, matched on
+	// method, path and query. Set MatchBody to also require identical request bodies.
+	Recorder struct {
+		// Transport is used to send requests upstream when recording. Defaults to http.DefaultTransport.
+		Transport http.RoundTripper
+		MatchBody bool
+
+		mu       sync.Mutex
+		path     string
+		mode     RecorderMode
+		cassette Cassette
+		used     []bool
+		secrets  []string
+	}
+)
+
+// NewRecorder creates a Recorder backed by the cassette at path. In replay mode the cassette must exist.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+
+	if mode == RecorderModeAuto {
+		r.mode = RecorderModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = RecorderModeReplay
+		}
+	}
+
+	if r.mode == RecorderModeReplay {
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &r.cassette); err != nil {
+			return nil, fmt.Errorf("gomonnify.testhelpers: malformed cassette %v: %v", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the mode the recorder is running in. For RecorderModeAuto this is the mode that was picked.
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// Redact registers secrets (e.g api key, secret key, BVNs) that must never be written to the cassette.
+func (r *Recorder) Redact(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range secrets {
+		if s != "" {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == RecorderModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+// Stop saves the cassette when recording. It is a no-op in replay mode.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode != RecorderModeRecord {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, raw, 0644)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reqHeader := req.Header.Clone()
+	if reqHeader.Get("Authorization") != "" {
+		reqHeader.Set("Authorization", Redacted)
+	}
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedHTTPRequest{
+			Method: req.Method,
+			URL:    r.redact(req.URL.RequestURI()),
+			Header: reqHeader,
+			Body:   r.redact(string(body)),
+		},
+		Response: RecordedHTTPResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+			Body:       r.redact(string(resBody)),
+		},
+	})
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uri := r.redact(req.URL.RequestURI())
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Request.Method != req.Method || in.Request.URL != uri {
+			continue
+		}
+		if r.MatchBody && in.Request.Body != r.redact(string(body)) {
+			continue
+		}
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %v", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("gomonnify.testhelpers: no recorded interaction left for %v %v in %v", req.Method, uri, r.path)
+}
+
+func (r *Recorder) redact(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.Replace(s, secret, Redacted, -1)
+	}
+	return redactedBodyFields.ReplaceAllString(s, fmt.Sprintf(`${1}"%v"`, Redacted))
+}
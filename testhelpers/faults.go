@@ -0,0 +1,173 @@
+package testhelpers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+// AnyRoute can be used in place of a route to apply a fault or latency to every endpoint.
+const AnyRoute string = "*"
+
+type (
+	// Fault describes how the MockServer should misbehave when it handles a request.
+	// StatusCode, ResponseCode and ResponseMessage shape an error response. Malformed returns a body that is not valid JSON.
+	// DropConnection closes the connection without responding, before the request is handled.
+	// DropResponse handles the request (state changes are applied) and then closes the connection without responding,
+	// which is how a timeout after Monnify has already accepted a transfer looks to the client.
+	Fault struct {
+		StatusCode      int
+		ResponseCode    string
+		ResponseMessage string
+		Malformed       bool
+		DropConnection  bool
+		DropResponse    bool
+	}
+
+	queuedFault struct {
+		route     string
+		remaining int
+		fault     Fault
+	}
+)
+
+// InjectFault makes the next n requests to route misbehave as described by fault.
+// route is either an endpoint pattern as listed in the server (e.g. /v1/bank-transfer/reserved-accounts/{accountReference}),
+// a concrete request path (e.g. /v1/disbursements/single) or AnyRoute.
+// Faults are consumed in the order they were injected.
+func (s *MockServer) InjectFault(route string, n int, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &queuedFault{route: route, remaining: n, fault: fault})
+}
+
+// FailNext makes the next n requests to route respond with the provided HTTP status and Monnify response code.
+// The responses always have requestSuccessful false, so an http.StatusOK statusCode mimics a rejection sent with HTTP 200.
+func (s *MockServer) FailNext(route string, n int, statusCode int, responseCode, message string) {
+	s.InjectFault(route, n, Fault{StatusCode: statusCode, ResponseCode: responseCode, ResponseMessage: message})
+}
+
+// MalformNext makes the next n requests to route respond with a body that is not valid JSON.
+func (s *MockServer) MalformNext(route string, n int) {
+	s.InjectFault(route, n, Fault{Malformed: true})
+}
+
+// DropNext makes the next n requests to route fail with a closed connection before they are handled.
+// Note that net/http transparently retries idempotent requests (e.g GET) once when a reused connection is dropped,
+// so a single dropped GET may not surface as an error to the caller.
+func (s *MockServer) DropNext(route string, n int) {
+	s.InjectFault(route, n, Fault{DropConnection: true})
+}
+
+// SetLatency delays every response from route by d. A zero d removes the latency.
+func (s *MockServer) SetLatency(route string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d == 0 {
+		delete(s.latency, route)
+		return
+	}
+	s.latency[route] = d
+}
+
+// ExpireToken invalidates the bearer token issued by the last login, the way Monnify does when it revokes a token early.
+// Bearer authenticated requests are rejected with a 401 until the client logs in again.
+func (s *MockServer) ExpireToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokenExpired = true
+}
+
+// ResetFaults removes every queued fault and latency and restores the bearer token.
+func (s *MockServer) ResetFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+	s.latency = map[string]time.Duration{}
+	s.tokenExpired = false
+}
+
+// applyFaults runs the latency and the next queued fault configured for the route. It returns true when the fault
+// has already produced the outcome of the request and the handler must not write a response.
+func (s *MockServer) applyFaults(w http.ResponseWriter, r *http.Request, rt route, vars map[string]string) bool {
+	s.mu.Lock()
+	delay := s.latency[AnyRoute]
+	if d, ok := s.latency[rt.pattern]; ok {
+		delay = d
+	}
+	if d, ok := s.latency[r.URL.Path]; ok {
+		delay = d
+	}
+
+	var fault *Fault
+	for i, f := range s.faults {
+		if f.route != AnyRoute && f.route != rt.pattern && f.route != r.URL.Path {
+			continue
+		}
+		ft := f.fault
+		fault = &ft
+		f.remaining--
+		if f.remaining <= 0 {
+			s.faults = append(s.faults[:i], s.faults[i+1:]...)
+		}
+		break
+	}
+
+	s.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	if fault == nil {
+		return false
+	}
+
+	switch {
+	case fault.DropConnection:
+		dropConnection(w)
+	case fault.DropResponse:
+		rt.handler(httptest.NewRecorder(), r, vars)
+		dropConnection(w)
+	case fault.Malformed:
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"requestSuccessful": true, "responseMessage": "success", "responseBody": {`)
+	default:
+		statusCode := fault.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusInternalServerError
+		}
+		responseCode := fault.ResponseCode
+		if responseCode == "" {
+			responseCode = "99"
+		}
+		message := fault.ResponseMessage
+		if message == "" {
+			message = http.StatusText(statusCode)
+		}
+		writeError(w, statusCode, responseCode, message)
+	}
+	return true
+}
+
+func dropConnection(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		log.Fatalf("gomonnify.testhelpers: unable to drop connection, response writer does not support hijacking")
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		log.Fatalf("gomonnify.testhelpers: unable to drop connection: %v", err)
+	}
+	conn.Close()
+}
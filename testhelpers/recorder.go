@@ -0,0 +1,158 @@
+package testhelpers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	authBasic  authType = "basic"
+	authBearer authType = "bearer"
+)
+
+type (
+	authType string
+
+	// RecordedRequest is a request received by the MockServer, captured before it is handled.
+	RecordedRequest struct {
+		Method string
+		Path   string
+		Route  string
+		Query  url.Values
+		Header http.Header
+		Body   []byte
+	}
+)
+
+// Decode unmarshals the recorded JSON body into v.
+func (rr RecordedRequest) Decode(v interface{}) error {
+	return json.Unmarshal(rr.Body, v)
+}
+
+// Requests returns every request received by the server, oldest first.
+func (s *MockServer) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received on path, oldest first.
+// path is either a concrete request path or an endpoint pattern as listed in the server.
+func (s *MockServer) RequestsTo(path string) []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []RecordedRequest
+	for _, rr := range s.requests {
+		if rr.Path == path || rr.Route == path {
+			result = append(result, rr)
+		}
+	}
+	return result
+}
+
+// AssertCalled returns an error unless path was called exactly n times.
+func (s *MockServer) AssertCalled(path string, n int) error {
+	if c := len(s.RequestsTo(path)); c != n {
+		return fmt.Errorf("gomonnify.testhelpers: expected %v to be called %v time(s), called %v time(s)", path, n, c)
+	}
+	return nil
+}
+
+// AssertNotCalled returns an error if path was called at all.
+func (s *MockServer) AssertNotCalled(path string) error {
+	return s.AssertCalled(path, 0)
+}
+
+// LastRequestBody decodes the JSON body of the most recent request to path into v.
+func (s *MockServer) LastRequestBody(path string, v interface{}) error {
+	requests := s.RequestsTo(path)
+	if len(requests) == 0 {
+		return fmt.Errorf("gomonnify.testhelpers: %v was not called", path)
+	}
+	return requests[len(requests)-1].Decode(v)
+}
+
+// ResetRequests clears the recorded requests.
+func (s *MockServer) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+}
+
+// SetCredentials makes the server only accept Basic auth with the provided api and secret key.
+// By default any well formed Basic credentials are accepted.
+func (s *MockServer) SetCredentials(apiKey, secretKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.apiKey = apiKey
+	s.secretKey = secretKey
+}
+
+func (s *MockServer) record(r *http.Request, rt route) {
+	var body []byte
+	if r.Body != nil {
+		body, _ = ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Route:  rt.pattern,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+}
+
+// authorize checks the request carries the kind of credentials the real API expects on the route.
+// It writes a 401 and returns false if it does not.
+func (s *MockServer) authorize(w http.ResponseWriter, r *http.Request, rt route) bool {
+	header := r.Header.Get("Authorization")
+
+	s.mu.Lock()
+	apiKey, secretKey, expired := s.apiKey, s.secretKey, s.tokenExpired
+	s.mu.Unlock()
+
+	switch rt.auth {
+	case authBasic:
+		if !strings.HasPrefix(header, "Basic ") {
+			writeError(w, http.StatusUnauthorized, "99", fmt.Sprintf("Basic authentication required on %v", rt.pattern))
+			return false
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Basic "))
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if err != nil || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			writeError(w, http.StatusUnauthorized, "99", "Malformed basic authentication credentials")
+			return false
+		}
+		if apiKey != "" && (parts[0] != apiKey || parts[1] != secretKey) {
+			writeError(w, http.StatusUnauthorized, "99", "Invalid api key or secret key")
+			return false
+		}
+	case authBearer:
+		if !strings.HasPrefix(header, "Bearer ") {
+			writeError(w, http.StatusUnauthorized, "99", fmt.Sprintf("Bearer authentication required on %v", rt.pattern))
+			return false
+		}
+		if strings.TrimPrefix(header, "Bearer ") != AccessToken || expired {
+			writeError(w, http.StatusUnauthorized, "99", "Access token expired or invalid")
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,1316 @@
+package testhelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TransferFee params.Amount = 100
+
+	TransferStatusPendingAuthorization string = "PENDING_AUTHORIZATION"
+	TransferStatusSuccess              string = "SUCCESS"
+	TransferStatusFailed               string = "FAILED"
+
+	BatchStatusPendingAuthorization string = "PENDING_AUTHORIZATION"
+	BatchStatusCompleted            string = "COMPLETED"
+	BatchStatusFailed               string = "FAILED"
+
+	dateCreatedLayout string = "02/01/2006 03:04:05 PM"
+	createdOnLayout   string = "2006-01-02 15:04:05.000"
+
+	// defaultPageSize is the page size of paged endpoints called without one, as on Monnify.
+	defaultPageSize int = 10
+)
+
+type (
+	// MockServer is an in-memory, stateful fake of the Monnify API.
+	// Reserved accounts, wallets, transfers and batches created through the API are kept in memory and can be
+	// retrieved, debited or deleted by later calls, the same way the real API behaves.
+	// It is seeded with the reserved account, wallet, transfer, batch and collection referenced by the constants in this
+	// package. Reset restores that state, so that every test can start from the same fixtures.
+	// Every request is recorded and checked for the Basic or Bearer credentials the real API expects on the endpoint.
+	MockServer struct {
+		*httptest.Server
+
+		mu                sync.Mutex
+		routes            []route
+		accounts          map[string]*mockReservedAccount
+		wallets           map[string]*mockWallet
+		transfers         map[string]*mockTransfer
+		transferOrder     []string
+		batches           map[string]*mockBatch
+		collections       map[string]*mockCollection
+		nextAccountNumber int64
+		nextWalletNumber  int64
+
+		requireAuthorization bool
+
+		faults       []*queuedFault
+		latency      map[string]time.Duration
+		tokenExpired bool
+
+		requests  []RecordedRequest
+		apiKey    string
+		secretKey string
+	}
+
+	route struct {
+		method  string
+		pattern string
+		auth    authType
+		handler func(w http.ResponseWriter, r *http.Request, vars map[string]string)
+	}
+
+	mockResponse struct {
+		RequestSuccessful bool        `json:"requestSuccessful"`
+		ResponseMessage   string      `json:"responseMessage"`
+		ResponseCode      string      `json:"responseCode"`
+		ResponseBody      interface{} `json:"responseBody,omitempty"`
+	}
+
+	mockPageSort struct {
+		Sorted   bool `json:"sorted"`
+		Unsorted bool `json:"unsorted"`
+		Empty    bool `json:"empty"`
+	}
+
+	mockPage struct {
+		Content  interface{} `json:"content"`
+		Pageable struct {
+			Sort       mockPageSort `json:"sort"`
+			PageSize   int          `json:"pageSize"`
+			PageNumber int          `json:"pageNumber"`
+			Offset     int          `json:"offset"`
+			Unpaged    bool         `json:"unpaged"`
+			Paged      bool         `json:"paged"`
+		} `json:"pageable"`
+		TotalElements    int          `json:"totalElements"`
+		TotalPages       int          `json:"totalPages"`
+		Last             bool         `json:"last"`
+		Sort             mockPageSort `json:"sort"`
+		First            bool         `json:"first"`
+		NumberOfElements int          `json:"numberOfElements"`
+		Size             int          `json:"size"`
+		Number           int          `json:"number"`
+		Empty            bool         `json:"empty"`
+	}
+
+	mockReservedAccount struct {
+		ContractCode          string        `json:"contractCode"`
+		AccountReference      string        `json:"accountReference"`
+		AccountName           string        `json:"accountName"`
+		CurrencyCode          string        `json:"currencyCode"`
+		CustomerEmail         string        `json:"customerEmail"`
+		CustomerName          string        `json:"customerName"`
+		AccountNumber         string        `json:"accountNumber"`
+		BankName              string        `json:"bankName"`
+		BankCode              string        `json:"bankCode"`
+		CollectionChannel     string        `json:"collectionChannel"`
+		ReservationReference  string        `json:"reservationReference"`
+		ReservedAccountType   string        `json:"reservedAccountType"`
+		Status                string        `json:"status"`
+		CreatedOn             string        `json:"createdOn"`
+		IncomeSplitConfig     []interface{} `json:"incomeSplitConfig"`
+		RestrictPaymentSource bool          `json:"restrictPaymentSource"`
+		Contract              struct {
+			Name                                       string  `json:"name"`
+			Code                                       string  `json:"code"`
+			Description                                *string `json:"description"`
+			SupportsAdvancedSettlementAccountSelection bool    `json:"supportsAdvancedSettlementAccountSelection"`
+			SweepToExternalAccount                     bool    `json:"sweepToExternalAccount"`
+		} `json:"contract"`
+
+		transactions []*mockAccountTransaction
+	}
+
+	mockAccountTransaction struct {
+		CustomerDTO struct {
+			Email        string `json:"email"`
+			Name         string `json:"name"`
+			MerchantCode string `json:"merchantCode"`
+		} `json:"customerDTO"`
+		ProviderAmount       params.Amount `json:"providerAmount"`
+		PaymentMethod        string        `json:"paymentMethod"`
+		CreatedOn            string        `json:"createdOn"`
+		Amount               params.Amount `json:"amount"`
+		Flagged              bool          `json:"flagged"`
+		ProviderCode         string        `json:"providerCode"`
+		Fee                  params.Amount `json:"fee"`
+		CurrencyCode         string        `json:"currencyCode"`
+		CompletedOn          string        `json:"completedOn"`
+		PaymentDescription   string        `json:"paymentDescription"`
+		PaymentStatus        string        `json:"paymentStatus"`
+		TransactionReference string        `json:"transactionReference"`
+		PaymentReference     string        `json:"paymentReference"`
+		MerchantCode         string        `json:"merchantCode"`
+		MerchantName         string        `json:"merchantName"`
+		PayableAmount        params.Amount `json:"payableAmount"`
+		AmountPaid           params.Amount `json:"amountPaid"`
+		Completed            bool          `json:"completed"`
+	}
+
+	mockWallet struct {
+		WalletName      string `json:"walletName"`
+		WalletReference string `json:"walletReference"`
+		CustomerName    string `json:"customerName"`
+		CustomerEmail   string `json:"customerEmail"`
+		FeeBearer       string `json:"feeBearer"`
+		BVNDetails      struct {
+			BVN         string `json:"bvn"`
+			DateOfBirth string `json:"bvnDateOfBirth"`
+		} `json:"bvnDetails"`
+		AccountNumber       string `json:"accountNumber"`
+		AccountName         string `json:"accountName"`
+		TopUpAccountDetails struct {
+			AccountNumber string `json:"accountNumber"`
+			AccountName   string `json:"accountName"`
+			BankCode      string `json:"bankCode"`
+			BankName      string `json:"bankName"`
+		} `json:"topUpAccountDetails"`
+
+		id        string
+		available params.Amount
+		ledger    params.Amount
+		entries   []*mockWalletEntry
+	}
+
+	mockWalletEntry struct {
+		WalletTransactionReference  string        `json:"walletTransactionReference"`
+		MonnifyTransactionReference string        `json:"monnifyTransactionReference"`
+		TransactionType             string        `json:"transactionType"`
+		Amount                      params.Amount `json:"amount"`
+		Fee                         params.Amount `json:"fee"`
+		BalanceBefore               params.Amount `json:"availableBalanceBefore"`
+		BalanceAfter                params.Amount `json:"availableBalanceAfter"`
+		Narration                   string        `json:"narration"`
+		Status                      string        `json:"status"`
+		TransactionDate             string        `json:"transactionDate"`
+
+		postedAt time.Time
+	}
+
+	mockCollection struct {
+		TransactionReference string        `json:"transactionReference"`
+		PaymentReference     string        `json:"paymentReference"`
+		AmountPaid           params.Amount `json:"amountPaid"`
+		TotalPayable         params.Amount `json:"totalPayable"`
+		SettlementAmount     params.Amount `json:"settlementAmount"`
+		PaidOn               string        `json:"paidOn"`
+		PaymentStatus        string        `json:"paymentStatus"`
+		PaymentDescription   string        `json:"paymentDescription"`
+		TransactionHash      string        `json:"transactionHash"`
+		Currency             string        `json:"currency"`
+		PaymentMethod        string        `json:"paymentMethod"`
+		Product              struct {
+			Type      string `json:"type"`
+			Reference string `json:"reference"`
+		} `json:"product"`
+		Customer struct {
+			Email string `json:"email"`
+			Name  string `json:"name"`
+		} `json:"customer"`
+		MetaData map[string]interface{} `json:"metaData"`
+	}
+
+	mockTransfer struct {
+		Amount        params.Amount `json:"amount"`
+		Reference     string        `json:"reference"`
+		Narration     string        `json:"narration"`
+		BankCode      string        `json:"bankCode"`
+		AccountNumber string        `json:"accountNumber"`
+		Currency      string        `json:"currency"`
+		AccountName   string        `json:"accountName"`
+		BankName      string        `json:"bankName"`
+		DateCreated   string        `json:"dateCreated"`
+		Fee           params.Amount `json:"fee"`
+		Status        string        `json:"status"`
+	}
+
+	mockBatch struct {
+		Title             string        `json:"title"`
+		TotalAmount       params.Amount `json:"totalAmount"`
+		TotalFee          params.Amount `json:"totalFee"`
+		BatchReference    string        `json:"batchReference"`
+		TotalTransactions int           `json:"totalTransactions"`
+		FailedCount       int           `json:"failedCount"`
+		SuccessfulCount   int           `json:"successfulCount"`
+		PendingCount      int           `json:"pendingCount"`
+		BatchStatus       string        `json:"batchStatus"`
+		DateCreated       string        `json:"dateCreated"`
+
+		items []*mockTransfer
+	}
+
+	transferRequest struct {
+		Amount        params.Amount `json:"amount"`
+		Reference     string        `json:"reference"`
+		Narration     string        `json:"narration"`
+		BankCode      string        `json:"bankCode"`
+		AccountNumber string        `json:"accountNumber"`
+		Currency      string        `json:"currency"`
+		WalletId      string        `json:"walletId"`
+	}
+)
+
+// NewMockServer starts a stateful fake of the Monnify API seeded with the fixtures in this package.
+// Call Close when done with it.
+func NewMockServer() *MockServer {
+	s := &MockServer{}
+	s.reset()
+
+	s.routes = []route{
+		{http.MethodPost, "/v1/auth/login", authBasic, s.login},
+		{http.MethodPost, "/v1/bank-transfer/reserved-accounts", authBearer, s.reserveAccount},
+		{http.MethodGet, "/v1/bank-transfer/reserved-accounts/transactions", authBearer, s.reservedAccountTransactions},
+		{http.MethodGet, "/v1/bank-transfer/reserved-accounts/{accountReference}", authBearer, s.reservedAccountDetails},
+		{http.MethodDelete, "/v1/bank-transfer/reserved-accounts/{accountNumber}", authBearer, s.deallocateReservedAccount},
+		{http.MethodPost, "/v1/disbursements/single", authBasic, s.singleTransfer},
+		{http.MethodPost, "/v1/disbursements/batch", authBasic, s.bulkTransfer},
+		{http.MethodPost, "/v1/disbursements/single/validate-otp", authBasic, s.authorizeSingleTransfer},
+		{http.MethodPost, "/v1/disbursements/batch/validate-otp", authBasic, s.authorizeBulkTransfer},
+		{http.MethodGet, "/v1/disbursements/single/summary", authBasic, s.singleTransferDetails},
+		{http.MethodGet, "/v1/disbursements/batch/summary", authBasic, s.bulkTransferDetails},
+		{http.MethodGet, "/v1/disbursements/bulk/{batchReference}/transactions", authBasic, s.bulkTransferTransactions},
+		{http.MethodGet, "/v1/disbursements/single/transactions", authBasic, s.singleTransferTransactions},
+		{http.MethodGet, "/v1/disbursements/account/validate", authBasic, s.validateAccountNumber},
+		{http.MethodGet, "/v1/disbursements/wallet-balance", authBasic, s.walletBalance},
+		{http.MethodPost, "/v1/disbursements/single/resend-otp", authBasic, s.resendOTP},
+		{http.MethodPost, "/v1/disbursements/wallet", authBasic, s.createWallet},
+		{http.MethodGet, "/v1/disbursements/wallet", authBasic, s.listWallets},
+		{http.MethodGet, "/v1/disbursements/wallet/transactions", authBasic, s.walletStatement},
+		{http.MethodPost, "/v1/merchant/transactions/init-transaction", authBasic, s.initializeTransaction},
+		{http.MethodGet, "/v2/transactions/{transactionReference}", authBearer, s.transactionStatus},
+		{http.MethodGet, "/v1/banks", authBearer, s.banks},
+		{http.MethodPost, "/v1/vas/bvn-account-match", authBearer, s.bvnAccountMatch},
+		{http.MethodPost, "/v1/vas/bvn-details-match", authBearer, s.bvnDetailsMatch},
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.ServeHTTP))
+	return s
+}
+
+// ServeHTTP routes the request to the matching endpoint handler.
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-type", "application/json")
+
+	var allowed []string
+	for _, rt := range s.routes {
+		vars, ok := matchPath(rt.pattern, r.URL.Path)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			allowed = append(allowed, rt.method)
+			continue
+		}
+		s.record(r, rt)
+		if !s.authorize(w, r, rt) {
+			return
+		}
+		if s.applyFaults(w, r, rt, vars) {
+			return
+		}
+		rt.handler(w, r, vars)
+		return
+	}
+
+	if len(allowed) > 0 {
+		log.Fatalf("gomonnify.testhelpers: %v request expected on %v endpoint, Got: %v", strings.Join(allowed, " or "), r.URL.Path, r.Method)
+	}
+	writeError(w, http.StatusNotFound, "99", fmt.Sprintf("No handler found for %v %v", r.Method, r.URL.Path))
+}
+
+// SetWalletBalance overwrites the available and ledger balance of the wallet, creating it if it does not exist.
+func (s *MockServer) SetWalletBalance(walletId string, available, ledger params.Amount) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wl, ok := s.wallets[walletId]
+	if !ok {
+		wl = &mockWallet{id: walletId}
+		s.wallets[walletId] = wl
+	}
+	wl.available = available
+	wl.ledger = ledger
+}
+
+// WalletBalance returns the available and ledger balance of the wallet as currently held by the server.
+func (s *MockServer) WalletBalance(walletId string) (available, ledger params.Amount, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wl, ok := s.wallets[walletId]
+	if !ok {
+		return 0, 0, false
+	}
+	return wl.available, wl.ledger, true
+}
+
+// CreditWallet tops up the wallet by amount and records the credit on its statement, as if it was funded
+// through its top-up account. Returns false if the wallet is unknown.
+func (s *MockServer) CreditWallet(walletId string, amount params.Amount) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wl, ok := s.wallets[walletId]
+	if !ok {
+		return false
+	}
+
+	now := time.Now()
+	before := wl.available
+	wl.available = wl.available + amount
+	wl.ledger = wl.ledger + amount
+	wl.entries = append(wl.entries, &mockWalletEntry{
+		WalletTransactionReference:  fmt.Sprintf("TOPUP-%v", now.UnixNano()),
+		MonnifyTransactionReference: fmt.Sprintf("MFDS|%v|TOPUP", now.Format("20060102150405")),
+		TransactionType:             "CREDIT",
+		Amount:                      amount,
+		BalanceBefore:               before,
+		BalanceAfter:                wl.available,
+		Narration:                   "Wallet top up",
+		Status:                      TransferStatusSuccess,
+		TransactionDate:             now.Format(createdOnLayout),
+		postedAt:                    now,
+	})
+	return true
+}
+
+// SetTransferStatus moves an existing single transfer to the provided status. Useful to simulate transfers that
+// settle or fail after submission. Returns false if the reference is unknown.
+func (s *MockServer) SetTransferStatus(reference, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.transfers[reference]
+	if !ok {
+		return false
+	}
+	t.Status = status
+	return true
+}
+
+// RequireAuthorization makes the transfers and batches created from now on wait for their OTP, as when two factor
+// authentication is enabled on the Monnify dashboard. They start as PENDING_AUTHORIZATION and move on to SUCCESS or
+// COMPLETED once authorized with ValidOTP.
+func (s *MockServer) RequireAuthorization(required bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requireAuthorization = required
+}
+
+// SetBatchStatus moves an existing bulk transfer to the provided batch status. Useful to simulate batches that are
+// still processing. Returns false if the batch reference is unknown.
+func (s *MockServer) SetBatchStatus(batchReference, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.batches[batchReference]
+	if !ok {
+		return false
+	}
+	b.BatchStatus = status
+	return true
+}
+
+// CreditReservedAccount records an inflow of amount on the reserved account, as if the customer paid into it.
+// Returns false if the account reference is unknown.
+func (s *MockServer) CreditReservedAccount(accountReference string, amount params.Amount) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.accounts[accountReference]
+	if !ok {
+		return false
+	}
+	a.transactions = append(a.transactions, newAccountTransaction(a, amount, fmt.Sprintf("MNFY|%v|%06d", time.Now().Format("20060102150405"), len(a.transactions)+1)))
+	return true
+}
+
+// PayTransaction completes an initialized transaction as if the customer paid the full amount by bank transfer.
+// The transaction hash is computed with the secret key set through SetCredentials, or SecretKey.
+// Returns false if the transaction reference is unknown.
+func (s *MockServer) PayTransaction(transactionReference string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[transactionReference]
+	if !ok {
+		return false
+	}
+
+	secretKey := s.secretKey
+	if secretKey == "" {
+		secretKey = SecretKey
+	}
+
+	c.AmountPaid = c.TotalPayable
+	c.SettlementAmount = c.TotalPayable
+	c.PaidOn = time.Now().Format(dateCreatedLayout)
+	c.PaymentStatus = "PAID"
+	c.PaymentMethod = PaymentMethod
+	c.TransactionHash = transactionHash(secretKey, c.PaymentReference, c.AmountPaid.String(), c.PaidOn, c.TransactionReference)
+	return true
+}
+
+// Reset discards everything created, queued or recorded since the server started, and seeds the fixtures again.
+func (s *MockServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reset()
+}
+
+func (s *MockServer) reset() {
+	s.accounts = map[string]*mockReservedAccount{}
+	s.wallets = map[string]*mockWallet{}
+	s.transfers = map[string]*mockTransfer{}
+	s.transferOrder = nil
+	s.batches = map[string]*mockBatch{}
+	s.collections = map[string]*mockCollection{}
+	s.nextAccountNumber = mustParseInt(AccountNumber) + 1
+	s.nextWalletNumber = mustParseInt(WalletAccountNumber)
+	s.requireAuthorization = false
+	s.faults = nil
+	s.latency = map[string]time.Duration{}
+	s.tokenExpired = false
+	s.requests = nil
+	s.apiKey, s.secretKey = "", ""
+	s.seed()
+}
+
+func (s *MockServer) seed() {
+	a := &mockReservedAccount{
+		ContractCode:         ContractCode,
+		AccountReference:     AccountReference,
+		AccountName:          AccountName,
+		CurrencyCode:         CurrencyCode,
+		CustomerEmail:        CustomerEmail,
+		CustomerName:         CustomerName,
+		AccountNumber:        AccountNumber,
+		BankName:             BankName,
+		BankCode:             BankCode,
+		CollectionChannel:    CollectionChannel,
+		ReservationReference: ReservationReference,
+		ReservedAccountType:  ReservedAccountType,
+		Status:               StatusActive,
+		CreatedOn:            CreatedOn,
+		IncomeSplitConfig:    []interface{}{},
+	}
+	a.Contract.Name = "Default Contract"
+	a.Contract.Code = ContractCode
+
+	tx := newAccountTransaction(a, Amount, "MNFY|20190724141227|003374")
+	tx.CreatedOn = CreatedOn
+	tx.CompletedOn = "2019-07-24T14:12:28.000+0000"
+	a.transactions = append(a.transactions, tx)
+	s.accounts[a.AccountReference] = a
+
+	s.wallets[WalletId] = &mockWallet{id: WalletId, available: AvailableBalance, ledger: LedgerBalance}
+
+	t := newTransfer(transferRequest{
+		Amount:        Amount,
+		Reference:     TransferReference,
+		Narration:     "TEST",
+		BankCode:      BankCode,
+		AccountNumber: AccountNumber,
+		Currency:      CurrencyCode,
+	})
+	t.Fee = TransferFee
+	t.Status = TransferStatusSuccess
+	s.transfers[t.Reference] = t
+	s.transferOrder = append(s.transferOrder, t.Reference)
+
+	item := *t
+	s.batches[BatchReference] = &mockBatch{
+		Title:             "TEST BATCH",
+		TotalAmount:       Amount,
+		TotalFee:          TransferFee,
+		BatchReference:    BatchReference,
+		TotalTransactions: 1,
+		SuccessfulCount:   1,
+		BatchStatus:       BatchStatusCompleted,
+		DateCreated:       t.DateCreated,
+		items:             []*mockTransfer{&item},
+	}
+}
+
+func newAccountTransaction(a *mockReservedAccount, amount params.Amount, reference string) *mockAccountTransaction {
+	now := time.Now()
+	tx := &mockAccountTransaction{
+		ProviderAmount:       ProviderAmount,
+		PaymentMethod:        PaymentMethod,
+		CreatedOn:            now.Format(createdOnLayout),
+		Amount:               amount,
+		ProviderCode:         ProviderCode,
+		Fee:                  79,
+		CurrencyCode:         a.CurrencyCode,
+		CompletedOn:          now.Format("2006-01-02T15:04:05.000-0700"),
+		PaymentDescription:   a.AccountName,
+		PaymentStatus:        "PAID",
+		TransactionReference: reference,
+		PaymentReference:     reference,
+		MerchantCode:         MerchantCode,
+		MerchantName:         "Test Limited",
+		PayableAmount:        amount,
+		AmountPaid:           amount,
+		Completed:            true,
+	}
+	tx.CustomerDTO.Email = a.CustomerEmail
+	tx.CustomerDTO.Name = a.CustomerName
+	tx.CustomerDTO.MerchantCode = MerchantCode
+	return tx
+}
+
+// Endpoint handlers
+
+func (s *MockServer) login(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	s.tokenExpired = false
+	s.mu.Unlock()
+
+	w.WriteHeader(200)
+	fmt.Fprintf(w, mockLoginResponseData())
+}
+
+func (s *MockServer) reserveAccount(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	var req struct {
+		AccountReference      string `json:"accountReference"`
+		AccountName           string `json:"accountName"`
+		CurrencyCode          string `json:"currencyCode"`
+		ContractCode          string `json:"contractCode"`
+		CustomerEmail         string `json:"customerEmail"`
+		CustomerName          string `json:"customerName"`
+		RestrictPaymentSource bool   `json:"restrictPaymentSource"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.AccountReference == "" || req.CustomerEmail == "" {
+		writeError(w, http.StatusBadRequest, "99", "accountReference and customerEmail are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Reserving an existing reference returns the existing reservation.
+	if a, ok := s.accounts[req.AccountReference]; ok {
+		writeSuccess(w, a)
+		return
+	}
+
+	a := &mockReservedAccount{
+		ContractCode:          req.ContractCode,
+		AccountReference:      req.AccountReference,
+		AccountName:           req.AccountName,
+		CurrencyCode:          req.CurrencyCode,
+		CustomerEmail:         req.CustomerEmail,
+		CustomerName:          req.CustomerName,
+		AccountNumber:         strconv.FormatInt(s.nextAccountNumber, 10),
+		BankName:              BankName,
+		BankCode:              BankCode,
+		CollectionChannel:     CollectionChannel,
+		ReservationReference:  fmt.Sprintf("RES%v", s.nextAccountNumber),
+		ReservedAccountType:   ReservedAccountType,
+		Status:                StatusActive,
+		CreatedOn:             time.Now().Format(createdOnLayout),
+		IncomeSplitConfig:     []interface{}{},
+		RestrictPaymentSource: req.RestrictPaymentSource,
+	}
+	a.Contract.Name = "Default Contract"
+	a.Contract.Code = req.ContractCode
+	s.nextAccountNumber++
+	s.accounts[a.AccountReference] = a
+
+	writeSuccess(w, a)
+}
+
+func (s *MockServer) reservedAccountDetails(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.accounts[vars["accountReference"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find reserved account")
+		return
+	}
+	writeSuccess(w, a)
+}
+
+func (s *MockServer) deallocateReservedAccount(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ref, a := range s.accounts {
+		if a.AccountNumber == vars["accountNumber"] {
+			delete(s.accounts, ref)
+			writeSuccess(w, a)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "99", "Cannot find reserved account")
+}
+
+func (s *MockServer) reservedAccountTransactions(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.accounts[r.URL.Query().Get("accountReference")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find reserved account")
+		return
+	}
+	writeSuccess(w, newPage(r.URL.Query(), a.transactions))
+}
+
+func (s *MockServer) singleTransfer(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	var req transferRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.transfers[req.Reference]; ok {
+		writeError(w, http.StatusBadRequest, "99", fmt.Sprintf("Duplicate reference %v", req.Reference))
+		return
+	}
+	if msg := validateTransfer(req); msg != "" {
+		writeError(w, http.StatusBadRequest, "99", msg)
+		return
+	}
+
+	wl, ok := s.wallets[s.walletId(req.WalletId)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find wallet")
+		return
+	}
+	if wl.available < req.Amount+TransferFee {
+		writeError(w, http.StatusBadRequest, "99", "Insufficient balance")
+		return
+	}
+
+	t := newTransfer(req)
+	wl.debit(t)
+	t.Status = TransferStatusSuccess
+	if s.requireAuthorization {
+		t.Status = TransferStatusPendingAuthorization
+	}
+	s.transfers[t.Reference] = t
+	s.transferOrder = append(s.transferOrder, t.Reference)
+
+	writeSuccess(w, t)
+}
+
+func (s *MockServer) bulkTransfer(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	var req struct {
+		Title               string            `json:"title"`
+		BatchReference      string            `json:"batchReference"`
+		Narration           string            `json:"narration"`
+		WalletId            string            `json:"walletId"`
+		OnValidationFailure string            `json:"onValidationFailure"`
+		TransactionList     []transferRequest `json:"transactionList"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.BatchReference == "" || len(req.TransactionList) == 0 {
+		writeError(w, http.StatusBadRequest, "99", "batchReference and transactionList are required")
+		return
+	}
+	if _, ok := s.batches[req.BatchReference]; ok {
+		writeError(w, http.StatusBadRequest, "99", fmt.Sprintf("Duplicate batch reference %v", req.BatchReference))
+		return
+	}
+
+	wl, ok := s.wallets[s.walletId(req.WalletId)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find wallet")
+		return
+	}
+
+	b := &mockBatch{
+		Title:             req.Title,
+		BatchReference:    req.BatchReference,
+		TotalTransactions: len(req.TransactionList),
+		BatchStatus:       BatchStatusCompleted,
+		DateCreated:       time.Now().Format(dateCreatedLayout),
+	}
+
+	invalid := map[int]bool{}
+	for i, item := range req.TransactionList {
+		if validateTransfer(item) != "" {
+			invalid[i] = true
+		}
+	}
+	if len(invalid) > 0 && req.OnValidationFailure == "BREAK" {
+		b.BatchStatus = BatchStatusFailed
+	}
+	if s.requireAuthorization && b.BatchStatus != BatchStatusFailed {
+		b.BatchStatus = BatchStatusPendingAuthorization
+	}
+
+	for i, item := range req.TransactionList {
+		t := newTransfer(item)
+		b.TotalAmount = b.TotalAmount + t.Amount
+		b.items = append(b.items, t)
+
+		if b.BatchStatus == BatchStatusFailed || invalid[i] || wl.available < t.Amount+TransferFee {
+			t.Status = TransferStatusFailed
+			t.Fee = 0
+			b.FailedCount++
+			continue
+		}
+		wl.debit(t)
+		t.Status = TransferStatusSuccess
+		if s.requireAuthorization {
+			t.Status = TransferStatusPendingAuthorization
+		}
+		b.TotalFee = b.TotalFee + t.Fee
+		b.SuccessfulCount++
+	}
+	s.batches[b.BatchReference] = b
+
+	writeSuccess(w, b)
+}
+
+func (s *MockServer) authorizeSingleTransfer(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	var req struct {
+		Reference         string `json:"reference"`
+		AuthorizationCode string `json:"authorizationCode"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.transfers[req.Reference]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find transfer")
+		return
+	}
+	if req.AuthorizationCode != ValidOTP {
+		writeError(w, http.StatusBadRequest, "99", "Invalid authorization code")
+		return
+	}
+	if t.Status == TransferStatusPendingAuthorization {
+		t.Status = TransferStatusSuccess
+	}
+	writeSuccess(w, t)
+}
+
+func (s *MockServer) authorizeBulkTransfer(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	var req struct {
+		Reference         string `json:"reference"`
+		AuthorizationCode string `json:"authorizationCode"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.batches[req.Reference]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find batch")
+		return
+	}
+	if req.AuthorizationCode != ValidOTP {
+		writeError(w, http.StatusBadRequest, "99", "Invalid authorization code")
+		return
+	}
+	if b.BatchStatus == BatchStatusPendingAuthorization {
+		b.BatchStatus = BatchStatusCompleted
+		for _, t := range b.items {
+			if t.Status == TransferStatusPendingAuthorization {
+				t.Status = TransferStatusSuccess
+			}
+		}
+	}
+	writeSuccess(w, b)
+}
+
+func (s *MockServer) singleTransferDetails(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.transfers[r.URL.Query().Get("reference")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find transfer")
+		return
+	}
+	writeSuccess(w, t)
+}
+
+func (s *MockServer) bulkTransferDetails(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.batches[r.URL.Query().Get("reference")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find batch")
+		return
+	}
+	writeSuccess(w, b)
+}
+
+func (s *MockServer) bulkTransferTransactions(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.batches[vars["batchReference"]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find batch")
+		return
+	}
+	writeSuccess(w, newPage(r.URL.Query(), b.items))
+}
+
+func (s *MockServer) singleTransferTransactions(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content := make([]*mockTransfer, 0, len(s.transferOrder))
+	for i := len(s.transferOrder) - 1; i >= 0; i-- {
+		content = append(content, s.transfers[s.transferOrder[i]])
+	}
+	writeSuccess(w, newPage(r.URL.Query(), content))
+}
+
+func (s *MockServer) validateAccountNumber(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	accountNumber := r.URL.Query().Get("accountNumber")
+	bankCode := r.URL.Query().Get("bankCode")
+	if !isNUBAN(accountNumber) {
+		writeError(w, http.StatusBadRequest, "99", "Invalid account number")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := AccountName
+	for _, a := range s.accounts {
+		if a.AccountNumber == accountNumber {
+			name = a.AccountName
+		}
+	}
+	writeSuccess(w, map[string]string{"accountNumber": accountNumber, "accountName": name, "bankCode": bankCode})
+}
+
+func (s *MockServer) walletBalance(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wl, ok := s.wallets[r.URL.Query().Get("walletId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find wallet")
+		return
+	}
+	writeSuccess(w, map[string]params.Amount{"availableBalance": wl.available, "ledgerBalance": wl.ledger})
+}
+
+func (s *MockServer) resendOTP(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	w.WriteHeader(200)
+	fmt.Fprintf(w, mockResendOTPResponseData())
+}
+
+func (s *MockServer) createWallet(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	var req struct {
+		WalletReference string `json:"walletReference"`
+		WalletName      string `json:"walletName"`
+		CustomerName    string `json:"customerName"`
+		CustomerEmail   string `json:"customerEmail"`
+		BVNDetails      struct {
+			BVN         string `json:"bvn"`
+			DateOfBirth string `json:"bvnDateOfBirth"`
+		} `json:"bvnDetails"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.wallets[req.WalletReference]; ok {
+		writeError(w, http.StatusBadRequest, "99", fmt.Sprintf("Duplicate wallet reference %v", req.WalletReference))
+		return
+	}
+
+	// Created wallets are addressed by their reference wherever the API expects a walletId.
+	wl := &mockWallet{
+		WalletName:      req.WalletName,
+		WalletReference: req.WalletReference,
+		CustomerName:    req.CustomerName,
+		CustomerEmail:   req.CustomerEmail,
+		FeeBearer:       "SELF",
+		AccountNumber:   strconv.FormatInt(s.nextWalletNumber, 10),
+		AccountName:     req.WalletName,
+		id:              req.WalletReference,
+	}
+	wl.BVNDetails.BVN = req.BVNDetails.BVN
+	wl.BVNDetails.DateOfBirth = req.BVNDetails.DateOfBirth
+	wl.TopUpAccountDetails.AccountNumber = strconv.FormatInt(s.nextAccountNumber, 10)
+	wl.TopUpAccountDetails.AccountName = req.WalletName
+	wl.TopUpAccountDetails.BankCode = BankCode
+	wl.TopUpAccountDetails.BankName = BankName
+	s.nextWalletNumber++
+	s.nextAccountNumber++
+	s.wallets[wl.id] = wl
+
+	writeSuccess(w, wl)
+}
+
+func (s *MockServer) listWallets(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	email := r.URL.Query().Get("customerEmail")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content := []*mockWallet{}
+	for _, wl := range s.wallets {
+		if wl.WalletReference != "" && (email == "" || wl.CustomerEmail == email) {
+			content = append(content, wl)
+		}
+	}
+	sort.Slice(content, func(i, j int) bool { return content[i].AccountNumber < content[j].AccountNumber })
+	writeSuccess(w, newPage(r.URL.Query(), content))
+}
+
+func (s *MockServer) walletStatement(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	q := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wl, ok := s.wallets[q.Get("walletId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "99", "Cannot find wallet")
+		return
+	}
+
+	content := []*mockWalletEntry{}
+	for _, e := range wl.entries {
+		ms := e.postedAt.UnixNano() / int64(time.Millisecond)
+		if from, err := strconv.ParseInt(q.Get("from"), 10, 64); err == nil && ms < from {
+			continue
+		}
+		if to, err := strconv.ParseInt(q.Get("to"), 10, 64); err == nil && ms > to {
+			continue
+		}
+		content = append(content, e)
+	}
+	writeSuccess(w, newPage(r.URL.Query(), content))
+}
+
+func (s *MockServer) initializeTransaction(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	var req struct {
+		Amount             params.Amount          `json:"amount"`
+		CustomerName       string                 `json:"customerName"`
+		CustomerEmail      string                 `json:"customerEmail"`
+		PaymentReference   string                 `json:"paymentReference"`
+		PaymentDescription string                 `json:"paymentDescription"`
+		CurrencyCode       string                 `json:"currencyCode"`
+		ContractCode       string                 `json:"contractCode"`
+		PaymentMethods     []string               `json:"paymentMethods"`
+		MetaData           map[string]interface{} `json:"metaData"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.PaymentReference == "" || req.CustomerEmail == "" || req.ContractCode == "" || req.Amount <= 0 {
+		writeError(w, http.StatusBadRequest, "99", "amount, paymentReference, customerEmail and contractCode are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.collections {
+		if c.PaymentReference == req.PaymentReference {
+			writeError(w, http.StatusBadRequest, "99", fmt.Sprintf("Duplicate payment reference %v", req.PaymentReference))
+			return
+		}
+	}
+
+	c := &mockCollection{
+		TransactionReference: fmt.Sprintf("MNFY|%v|%06d", time.Now().Format("20060102150405"), len(s.collections)+1),
+		PaymentReference:     req.PaymentReference,
+		TotalPayable:         req.Amount,
+		PaymentStatus:        "PENDING",
+		PaymentDescription:   req.PaymentDescription,
+		Currency:             req.CurrencyCode,
+		MetaData:             req.MetaData,
+	}
+	c.Product.Type = "API_NOTIFICATION"
+	c.Product.Reference = req.PaymentReference
+	c.Customer.Email = req.CustomerEmail
+	c.Customer.Name = req.CustomerName
+	s.collections[c.TransactionReference] = c
+
+	methods := req.PaymentMethods
+	if len(methods) == 0 {
+		methods = []string{"CARD", "ACCOUNT_TRANSFER"}
+	}
+
+	writeSuccess(w, map[string]interface{}{
+		"transactionReference": c.TransactionReference,
+		"paymentReference":     c.PaymentReference,
+		"merchantName":         "Test Merchant",
+		"apiKey":               s.apiKey,
+		"enabledPaymentMethod": methods,
+		"checkoutUrl":          fmt.Sprintf("https://sandbox.sdk.monnify.com/checkout/%v", c.TransactionReference),
+	})
+}
+
+func (s *MockServer) transactionStatus(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	s.mu.Lock()
+	var collection mockCollection
+	c, ok := s.collections[vars["transactionReference"]]
+	if ok {
+		collection = *c
+	}
+	s.mu.Unlock()
+	if ok {
+		writeSuccess(w, collection)
+		return
+	}
+
+	if vars["transactionReference"] != TransferReference {
+		writeError(w, http.StatusNotFound, "99", "Cannot find transaction")
+		return
+	}
+	w.WriteHeader(200)
+	fmt.Fprintf(w, mockTransactionStatusResponseData())
+}
+
+func (s *MockServer) banks(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	w.WriteHeader(200)
+	fmt.Fprintf(w, mockGetBanksResponseData())
+}
+
+func (s *MockServer) bvnAccountMatch(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	w.WriteHeader(200)
+	fmt.Fprintf(w, mockBVNAccountMatchResponseData())
+}
+
+func (s *MockServer) bvnDetailsMatch(w http.ResponseWriter, r *http.Request, vars map[string]string) {
+	w.WriteHeader(200)
+	fmt.Fprintf(w, mockBVNDetailsResponseData())
+}
+
+// Helpers
+
+// walletId falls back to the seeded wallet when the request does not name one.
+func (s *MockServer) walletId(walletId string) string {
+	if walletId == "" {
+		return WalletId
+	}
+	return walletId
+}
+
+// debit takes the transfer amount and fee off the wallet and records both on its statement.
+func (wl *mockWallet) debit(t *mockTransfer) {
+	now := time.Now()
+	t.Fee = TransferFee
+
+	before := wl.available
+	wl.available = wl.available - t.Amount
+	wl.ledger = wl.ledger - t.Amount
+	wl.entries = append(wl.entries, &mockWalletEntry{
+		WalletTransactionReference:  t.Reference,
+		MonnifyTransactionReference: fmt.Sprintf("MFDS|%v|%v", now.Format("20060102150405"), t.Reference),
+		TransactionType:             "DEBIT",
+		Amount:                      t.Amount,
+		BalanceBefore:               before,
+		BalanceAfter:                wl.available,
+		Narration:                   t.Narration,
+		Status:                      TransferStatusSuccess,
+		TransactionDate:             now.Format(createdOnLayout),
+		postedAt:                    now,
+	})
+
+	before = wl.available
+	wl.available = wl.available - t.Fee
+	wl.ledger = wl.ledger - t.Fee
+	wl.entries = append(wl.entries, &mockWalletEntry{
+		WalletTransactionReference:  fmt.Sprintf("%v-FEE", t.Reference),
+		MonnifyTransactionReference: fmt.Sprintf("MFDS|%v|%v", now.Format("20060102150405"), t.Reference),
+		TransactionType:             "FEE",
+		Fee:                         t.Fee,
+		BalanceBefore:               before,
+		BalanceAfter:                wl.available,
+		Narration:                   "Transfer fee",
+		Status:                      TransferStatusSuccess,
+		TransactionDate:             now.Format(createdOnLayout),
+		postedAt:                    now,
+	})
+}
+
+func newTransfer(req transferRequest) *mockTransfer {
+	return &mockTransfer{
+		Amount:        req.Amount,
+		Reference:     req.Reference,
+		Narration:     req.Narration,
+		BankCode:      req.BankCode,
+		AccountNumber: req.AccountNumber,
+		Currency:      req.Currency,
+		AccountName:   AccountName,
+		BankName:      BankName,
+		DateCreated:   time.Now().Format(dateCreatedLayout),
+	}
+}
+
+func validateTransfer(req transferRequest) string {
+	switch {
+	case req.Reference == "":
+		return "reference is required"
+	case req.Amount <= 0:
+		return "amount must be greater than zero"
+	case !isNUBAN(req.AccountNumber):
+		return "Invalid account number"
+	case req.BankCode == "":
+		return "bankCode is required"
+	}
+	return ""
+}
+
+func isNUBAN(accountNumber string) bool {
+	if len(accountNumber) != 10 {
+		return false
+	}
+	_, err := strconv.ParseUint(accountNumber, 10, 64)
+	return err == nil
+}
+
+// matchPath matches path against a pattern where {name} segments match any single path segment.
+func matchPath(pattern, path string) (map[string]string, bool) {
+	pp := strings.Split(strings.Trim(pattern, "/"), "/")
+	ps := strings.Split(strings.Trim(path, "/"), "/")
+	if len(pp) != len(ps) {
+		return nil, false
+	}
+
+	vars := map[string]string{}
+	for i := range pp {
+		if strings.HasPrefix(pp[i], "{") && strings.HasSuffix(pp[i], "}") {
+			vars[strings.Trim(pp[i], "{}")] = ps[i]
+			continue
+		}
+		if pp[i] != ps[i] {
+			return nil, false
+		}
+	}
+	return vars, true
+}
+
+// newPage returns the page of content asked for by the pageNo and pageSize query parameters (page and size on the
+// reserved account endpoints), numbered from 0 like Monnify does. Pages past the last one are empty.
+func newPage[T any](q url.Values, content []T) mockPage {
+	pageNo := queryInt(q, 0, "pageNo", "page")
+	if pageNo < 0 {
+		pageNo = 0
+	}
+	pageSize := queryInt(q, defaultPageSize, "pageSize", "size")
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	n := len(content)
+	start := pageNo * pageSize
+	if start > n {
+		start = n
+	}
+	end := start + pageSize
+	if end > n {
+		end = n
+	}
+	items := make([]T, end-start)
+	copy(items, content[start:end])
+
+	p := mockPage{
+		Content:          items,
+		TotalElements:    n,
+		TotalPages:       (n + pageSize - 1) / pageSize,
+		First:            pageNo == 0,
+		NumberOfElements: len(items),
+		Size:             pageSize,
+		Number:           pageNo,
+		Empty:            len(items) == 0,
+	}
+	p.Last = pageNo >= p.TotalPages-1
+	p.Pageable.PageSize = pageSize
+	p.Pageable.PageNumber = pageNo
+	p.Pageable.Offset = start
+	p.Pageable.Paged = true
+	p.Sort.Unsorted = true
+	p.Pageable.Sort = p.Sort
+	return p
+}
+
+// queryInt returns the value of the first of keys set in q, or def when none is set or it is not a number.
+func queryInt(q url.Values, def int, keys ...string) int {
+	for _, k := range keys {
+		if v := q.Get(k); v != "" {
+			if i, err := strconv.Atoi(v); err == nil {
+				return i
+			}
+			return def
+		}
+	}
+	return def
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "99", fmt.Sprintf("Malformed request body: %v", err))
+		return false
+	}
+	return true
+}
+
+func writeSuccess(w http.ResponseWriter, body interface{}) {
+	writeJSON(w, http.StatusOK, mockResponse{RequestSuccessful: true, ResponseMessage: "success", ResponseCode: "0", ResponseBody: body})
+}
+
+func writeError(w http.ResponseWriter, statusCode int, responseCode, message string) {
+	writeJSON(w, statusCode, mockResponse{RequestSuccessful: false, ResponseMessage: message, ResponseCode: responseCode})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Fatalf("gomonnify.testhelpers: unable to encode mock response: %v", err)
+	}
+	w.WriteHeader(statusCode)
+	w.Write(b)
+}
+
+func mustParseInt(s string) int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		log.Fatalf("gomonnify.testhelpers: %v is not numeric", s)
+	}
+	return n
+}
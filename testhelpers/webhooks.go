@@ -0,0 +1,299 @@
+package testhelpers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+	"net/http"
+	"time"
+)
+
+const (
+	WebhookEventCollection           WebhookEventType = "SUCCESSFUL_TRANSACTION"
+	WebhookEventDisbursementSuccess  WebhookEventType = "SUCCESSFUL_DISBURSEMENT"
+	WebhookEventDisbursementFailure  WebhookEventType = "FAILED_DISBURSEMENT"
+	WebhookEventDisbursementReversal WebhookEventType = "REVERSED_DISBURSEMENT"
+	WebhookEventRefundSuccess        WebhookEventType = "SUCCESSFUL_REFUND"
+	WebhookEventRefundFailure        WebhookEventType = "FAILED_REFUND"
+	WebhookEventSettlement           WebhookEventType = "SETTLEMENT"
+	WebhookEventMandate              WebhookEventType = "MANDATE_UPDATE"
+
+	// WebhookSignatureHeader is the header Monnify sends the payload signature in.
+	WebhookSignatureHeader string = "monnify-signature"
+
+	webhookDateLayout string = "2006-01-02 15:04:05.000"
+)
+
+type (
+	WebhookEventType string
+
+	// WebhookEvent builds signed Monnify webhook notifications so webhook handlers can be driven end-to-end in tests.
+	// Every event starts from the fixtures in this package; use the With* methods to change what matters to the test.
+	//	err := testhelpers.NewWebhookEvent(testhelpers.WebhookEventDisbursementFailure).
+	//		WithReference("PAYOUT-1").
+	//		WithAmount(params.Naira(2500)).
+	//		SendTo(server.URL + "/webhooks/monnify")
+	WebhookEvent struct {
+		eventType        WebhookEventType
+		secretKey        string
+		amount           params.Amount
+		fee              params.Amount
+		reference        string
+		paymentReference string
+		customerName     string
+		customerEmail    string
+		occurredAt       time.Time
+		fields           map[string]interface{}
+	}
+)
+
+// NewWebhookEvent starts a webhook notification of the provided type, signed with SecretKey by default.
+func NewWebhookEvent(eventType WebhookEventType) *WebhookEvent {
+	return &WebhookEvent{
+		eventType:        eventType,
+		secretKey:        SecretKey,
+		amount:           Amount,
+		fee:              TransferFee,
+		reference:        TransferReference,
+		paymentReference: PaymentReference,
+		customerName:     CustomerName,
+		customerEmail:    CustomerEmail,
+		occurredAt:       time.Now(),
+		fields:           map[string]interface{}{},
+	}
+}
+
+// WithSecretKey sets the client secret key used to sign the payload.
+func (e *WebhookEvent) WithSecretKey(secretKey string) *WebhookEvent {
+	e.secretKey = secretKey
+	return e
+}
+
+// WithAmount sets the amount paid, transferred, refunded or settled, depending on the event type.
+func (e *WebhookEvent) WithAmount(amount params.Amount) *WebhookEvent {
+	e.amount = amount
+	return e
+}
+
+// WithFee sets the fee charged on disbursement events.
+func (e *WebhookEvent) WithFee(fee params.Amount) *WebhookEvent {
+	e.fee = fee
+	return e
+}
+
+// WithReference sets the transaction reference of collections and refunds, the transfer reference of disbursements,
+// the settlement reference of settlements and the mandate reference of mandates.
+func (e *WebhookEvent) WithReference(reference string) *WebhookEvent {
+	e.reference = reference
+	return e
+}
+
+// WithPaymentReference sets the merchant payment reference of collections.
+func (e *WebhookEvent) WithPaymentReference(paymentReference string) *WebhookEvent {
+	e.paymentReference = paymentReference
+	return e
+}
+
+// WithCustomer sets the customer on collection and mandate events.
+func (e *WebhookEvent) WithCustomer(name, email string) *WebhookEvent {
+	e.customerName = name
+	e.customerEmail = email
+	return e
+}
+
+// WithTime sets when the event happened (paidOn, completedOn, settlementTime...).
+func (e *WebhookEvent) WithTime(t time.Time) *WebhookEvent {
+	e.occurredAt = t
+	return e
+}
+
+// WithField sets or overrides any field of the event data, e.g. WithField("metaData", map[string]string{"orderId": "1"}).
+func (e *WebhookEvent) WithField(key string, value interface{}) *WebhookEvent {
+	e.fields[key] = value
+	return e
+}
+
+// EventData returns the eventData object of the notification.
+func (e *WebhookEvent) EventData() map[string]interface{} {
+	var data map[string]interface{}
+
+	at := e.occurredAt.Format(webhookDateLayout)
+	amount := e.amount.String()
+
+	switch e.eventType {
+	case WebhookEventCollection:
+		paidOn := e.occurredAt.Format("02/01/2006 03:04:05 PM")
+		data = map[string]interface{}{
+			"transactionReference": e.reference,
+			"paymentReference":     e.paymentReference,
+			"amountPaid":           amount,
+			"totalPayable":         amount,
+			"settlementAmount":     e.amount.Sub(e.fee).String(),
+			"paidOn":               paidOn,
+			"paymentStatus":        "PAID",
+			"paymentDescription":   "Payment",
+			"transactionHash":      transactionHash(e.secretKey, e.paymentReference, amount, paidOn, e.reference),
+			"currency":             CurrencyCode,
+			"paymentMethod":        PaymentMethod,
+			"product": map[string]string{
+				"type":      "RESERVED_ACCOUNT",
+				"reference": AccountReference,
+			},
+			"accountDetails": map[string]string{
+				"accountName":   CustomerName,
+				"accountNumber": "******7503",
+				"bankCode":      "000001",
+				"amountPaid":    amount,
+			},
+			"customer": map[string]string{
+				"name":  e.customerName,
+				"email": e.customerEmail,
+			},
+			"metaData": map[string]string{},
+		}
+
+	case WebhookEventDisbursementSuccess, WebhookEventDisbursementFailure, WebhookEventDisbursementReversal:
+		status, description := "SUCCESS", "Approved or completed successfully"
+		switch e.eventType {
+		case WebhookEventDisbursementFailure:
+			status, description = "FAILED", "Transaction failed"
+		case WebhookEventDisbursementReversal:
+			status, description = "REVERSED", "Transaction reversed"
+		}
+		data = map[string]interface{}{
+			"amount":                   e.amount,
+			"fee":                      e.fee,
+			"reference":                e.reference,
+			"transactionReference":     fmt.Sprintf("MFDS|%v|%v", e.occurredAt.Format("20060102150405"), e.reference),
+			"transactionDescription":   description,
+			"narration":                "Transfer",
+			"destinationAccountNumber": AccountNumber,
+			"destinationAccountName":   AccountName,
+			"destinationBankCode":      BankCode,
+			"destinationBankName":      BankName,
+			"currency":                 CurrencyCode,
+			"sessionId":                fmt.Sprintf("090405%v", e.occurredAt.Format("060102150405")),
+			"createdOn":                at,
+			"completedOn":              at,
+			"status":                   status,
+		}
+
+	case WebhookEventRefundSuccess, WebhookEventRefundFailure:
+		status := "COMPLETED"
+		if e.eventType == WebhookEventRefundFailure {
+			status = "FAILED"
+		}
+		data = map[string]interface{}{
+			"refundReference":      fmt.Sprintf("REF-%v", e.reference),
+			"transactionReference": e.reference,
+			"refundAmount":         e.amount,
+			"refundStatus":         status,
+			"merchantReason":       "Customer request",
+			"customerNote":         "Refund",
+			"createdOn":            at,
+			"completedOn":          at,
+		}
+
+	case WebhookEventSettlement:
+		data = map[string]interface{}{
+			"settlementReference":      e.reference,
+			"amount":                   e.amount,
+			"settlementTime":           at,
+			"destinationAccountNumber": AccountNumber,
+			"destinationAccountName":   AccountName,
+			"destinationBankName":      BankName,
+			"transactionsCount":        1,
+			"transactions": []map[string]interface{}{{
+				"transactionReference": TransferReference,
+				"paymentReference":     e.paymentReference,
+				"amountPaid":           amount,
+				"settlementAmount":     e.amount,
+				"customer":             map[string]string{"name": e.customerName, "email": e.customerEmail},
+			}},
+		}
+
+	case WebhookEventMandate:
+		data = map[string]interface{}{
+			"mandateReference":      e.reference,
+			"mandateCode":           fmt.Sprintf("MTDD|%v", e.occurredAt.Format("20060102150405")),
+			"mandateStatus":         "ACTIVE",
+			"mandateAmount":         e.amount,
+			"customerName":          e.customerName,
+			"customerEmailAddress":  e.customerEmail,
+			"customerAccountNumber": AccountNumber,
+			"customerBankCode":      BankCode,
+			"startDate":             at,
+			"endDate":               e.occurredAt.AddDate(1, 0, 0).Format(webhookDateLayout),
+		}
+
+	default:
+		data = map[string]interface{}{}
+	}
+
+	for k, v := range e.fields {
+		data[k] = v
+	}
+	return data
+}
+
+// Payload returns the JSON body of the notification.
+func (e *WebhookEvent) Payload() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"eventType": e.eventType,
+		"eventData": e.EventData(),
+	})
+}
+
+// Signature returns the monnify-signature of the payload.
+func (e *WebhookEvent) Signature() (string, error) {
+	payload, err := e.Payload()
+	if err != nil {
+		return "", err
+	}
+	return SignWebhookPayload(e.secretKey, payload), nil
+}
+
+// SendTo POSTs the signed notification to url, the same way Monnify delivers webhooks.
+// It returns an error if the request fails or the handler does not respond with a 2xx status.
+func (e *WebhookEvent) SendTo(url string) error {
+	payload, err := e.Payload()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(e.secretKey, payload))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("gomonnify.testhelpers: webhook %v rejected by %v with status %v", e.eventType, url, res.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookPayload computes the monnify-signature of a webhook body: the hex encoded HMAC-SHA512 of the body
+// keyed with the client secret key.
+func SignWebhookPayload(secretKey string, payload []byte) string {
+	mac := hmac.New(sha512.New, []byte(secretKey))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func transactionHash(secretKey, paymentReference, amountPaid, paidOn, transactionReference string) string {
+	h := sha512.New()
+	h.Write([]byte(fmt.Sprintf("%v|%v|%v|%v|%v", secretKey, paymentReference, amountPaid, paidOn, transactionReference)))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,108 @@
+package gomonnify
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampLayouts lists every date format Monnify is known to emit, e.g
+// createdOn "2020-07-26 19:24:39.113", paidOn "26/02/2020 09:38:13 AM" and completedOn "2019-07-24T14:12:28.000+0000".
+// Layouts without a zone are read as Lagos time.
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"02/01/2006 03:04:05 PM",
+	"02/01/2006 15:04:05",
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02T15:04:05Z0700",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// lagos is the timezone Monnify reports dates in. It falls back to a fixed WAT (UTC+1) zone when the tz database is
+// not available on the host.
+var lagos = loadLagos()
+
+type (
+	// Timestamp is a date returned by Monnify, parsed from whichever format the endpoint uses and located in Lagos time.
+	// The string exactly as Monnify sent it is kept and available through Raw, which is what must be used when
+	// computing hashes. A value in a format that is not recognised decodes to a zero time but keeps its Raw value.
+	Timestamp struct {
+		time.Time
+		raw string
+	}
+)
+
+// ParseTimestamp parses s in any of the date formats Monnify emits.
+func ParseTimestamp(s string) (Timestamp, error) {
+	var lastErr error
+	for _, layout := range timestampLayouts {
+		t, err := time.ParseInLocation(layout, s, lagos)
+		if err == nil {
+			return Timestamp{Time: t.In(lagos), raw: s}, nil
+		}
+		lastErr = err
+	}
+	return Timestamp{raw: s}, lastErr
+}
+
+// Raw returns the timestamp exactly as Monnify sent it, or an empty string if it was not received from Monnify.
+func (t Timestamp) Raw() string {
+	return t.raw
+}
+
+// MarshalJSON implements json.Marshaler. The original value (string or epoch milliseconds) is written back when there
+// is one, so the value round trips unchanged.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if _, err := strconv.ParseInt(t.raw, 10, 64); err == nil {
+		return []byte(t.raw), nil
+	}
+	if t.raw != "" {
+		return json.Marshal(t.raw)
+	}
+	if t.Time.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Time.In(lagos).Format(timestampLayouts[0]))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts any of the string formats Monnify emits, epoch milliseconds,
+// empty strings and null.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if str == "null" {
+		*t = Timestamp{}
+		return nil
+	}
+
+	if !strings.HasPrefix(str, `"`) {
+		ms, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			return err
+		}
+		*t = Timestamp{Time: time.Unix(0, ms*int64(time.Millisecond)).In(lagos), raw: str}
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	if str == "" {
+		*t = Timestamp{}
+		return nil
+	}
+	*t, _ = ParseTimestamp(str)
+	return nil
+}
+
+func loadLagos() *time.Location {
+	loc, err := time.LoadLocation("Africa/Lagos")
+	if err != nil {
+		return time.FixedZone("WAT", 60*60)
+	}
+	return loc
+}
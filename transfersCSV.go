@@ -0,0 +1,246 @@
+package gomonnify
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+	"io"
+	"strings"
+)
+
+// exportPageSize is the page size used to fetch the transactions of a batch for ExportBulkTransferCSV.
+const exportPageSize = 100
+
+// DefaultCSVColumns are the headers ReadTransfersCSV expects when no mapping is provided.
+var DefaultCSVColumns = CSVColumns{
+	Amount:        "amount",
+	BankCode:      "bankCode",
+	AccountNumber: "accountNumber",
+	Narration:     "narration",
+	Reference:     "reference",
+	Currency:      "currency",
+}
+
+// TransfersCSVHeader is the header row written by WriteTransfersCSV.
+var TransfersCSVHeader = []string{
+	"reference", "amount", "fee", "currency", "bankCode", "bankName", "accountNumber", "accountName", "narration",
+	"status", "dateCreated",
+}
+
+type (
+	// CSVColumns maps the fields of a transfer to the header of the CSV column holding them. Headers are matched
+	// without regard to case or surrounding spaces. Amount, BankCode, AccountNumber and Reference are required,
+	// Narration and Currency may be left empty to use the defaults passed to ReadTransfersCSV.
+	CSVColumns struct {
+		Amount        string
+		BankCode      string
+		AccountNumber string
+		Narration     string
+		Reference     string
+		Currency      string
+	}
+
+	// CSVRowError describes why a row of a CSV file could not be imported. Row is the line the row starts on, the
+	// header being on line 1.
+	CSVRowError struct {
+		Row int
+		Err error
+	}
+
+	// CSVRowErrors lists every row of a CSV file that could not be imported.
+	CSVRowErrors []CSVRowError
+)
+
+func (e CSVRowError) Error() string {
+	return fmt.Sprintf("row %v: %v", e.Row, e.Err)
+}
+
+func (e CSVRowError) Unwrap() error {
+	return e.Err
+}
+
+func (e CSVRowErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, re := range e {
+		msgs = append(msgs, re.Error())
+	}
+	return fmt.Sprintf("invalid rows: %v", strings.Join(msgs, "; "))
+}
+
+// ReadTransfersCSV reads transfers from a CSV file with a header row, mapping its columns with columns.
+// Amounts are in naira and may use thousands separators, e.g. 1,500.50. defaultNarration is used for rows without a
+// narration and the currency defaults to NGN.
+//
+// Every row is validated like SingleTransfer would and references must be unique in the file. The transfers of the
+// valid rows are returned along with CSVRowErrors listing the others, so a file can be fixed in a single pass. Rows
+// that are not valid CSV, e.g. with a stray quote, are listed too and reading goes on with the next line the reader
+// can make sense of; an unterminated quoted field takes the rest of the file with it.
+// An error is returned instead when the file itself cannot be read or is missing a required column.
+func ReadTransfersCSV(r io.Reader, columns CSVColumns, defaultNarration string) ([]params.SingleTransferParam, CSVRowErrors, error) {
+	if columns.Amount == "" || columns.BankCode == "" || columns.AccountNumber == "" || columns.Reference == "" {
+		return nil, nil, errors.New("malformed column mapping - amount, bank code, account number and reference are required")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, errors.New("csv file is empty")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index := map[string]int{}
+	for i, h := range header {
+		index[normalizeHeader(h)] = i
+	}
+
+	var amountCol, bankCodeCol, accountNumberCol, referenceCol, narrationCol, currencyCol int
+	for _, c := range []struct {
+		name  string
+		index *int
+	}{
+		{columns.Amount, &amountCol},
+		{columns.BankCode, &bankCodeCol},
+		{columns.AccountNumber, &accountNumberCol},
+		{columns.Reference, &referenceCol},
+		{columns.Narration, &narrationCol},
+		{columns.Currency, &currencyCol},
+	} {
+		*c.index = -1
+		if c.name == "" {
+			continue
+		}
+		i, ok := index[normalizeHeader(c.name)]
+		if !ok {
+			return nil, nil, fmt.Errorf("csv file has no %v column", c.name)
+		}
+		*c.index = i
+	}
+
+	var transfers []params.SingleTransferParam
+	var rowErrs CSVRowErrors
+	seen := map[string]int{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrs = append(rowErrs, CSVRowError{Row: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		row, _ := reader.FieldPos(0)
+
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		transfer := params.SingleTransferParam{
+			BankCode:      field(bankCodeCol),
+			AccountNumber: field(accountNumberCol),
+			Reference:     field(referenceCol),
+			Narration:     field(narrationCol),
+			Currency:      params.Currency(strings.ToUpper(field(currencyCol))),
+		}
+		if transfer.Narration == "" {
+			transfer.Narration = defaultNarration
+		}
+		if transfer.Currency == "" {
+			transfer.Currency = CurrencyNGN
+		}
+
+		amount, err := params.ParseAmount(strings.ReplaceAll(field(amountCol), ",", ""))
+		if err != nil {
+			rowErrs = append(rowErrs, CSVRowError{Row: row, Err: fmt.Errorf("amount %q is not a valid amount", field(amountCol))})
+			continue
+		}
+		transfer.Amount = amount
+
+		if err := transfer.Validate(); err != nil {
+			rowErrs = append(rowErrs, CSVRowError{Row: row, Err: err})
+			continue
+		}
+		if first, ok := seen[transfer.Reference]; ok {
+			rowErrs = append(rowErrs, CSVRowError{Row: row, Err: fmt.Errorf("reference %v is already used on row %v", transfer.Reference, first)})
+			continue
+		}
+		seen[transfer.Reference] = row
+		transfers = append(transfers, transfer)
+	}
+	return transfers, rowErrs, nil
+}
+
+// WriteTransfersCSV writes transfers to w as CSV, with TransfersCSVHeader as the header row. Amounts and fees are
+// written in naira with two decimal places and dates as Monnify sent them. Text cells starting with = + - @ or a tab
+// are prefixed with ' so that spreadsheets do not run them as formulas.
+func WriteTransfersCSV(w io.Writer, transfers []SingleTransferDetails) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(TransfersCSVHeader); err != nil {
+		return err
+	}
+	for _, t := range transfers {
+		err := writer.Write([]string{
+			csvText(t.Reference), t.Amount.String(), t.Fee.String(), csvText(t.Currency), csvText(t.BankCode),
+			csvText(t.BankName), csvText(t.AccountNumber), csvText(t.AccountName), csvText(t.Narration),
+			csvText(string(t.Status)), t.DateCreated.Raw(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportBulkTransferCSV fetches every transaction of the batch with BulkTransferTransactions and writes them to w
+// with WriteTransfersCSV, so the final statuses and fees can be reconciled.
+func ExportBulkTransferCSV(d DisbursementsAPI, batchReference string, w io.Writer) error {
+	var transfers []SingleTransferDetails
+	for pageNo := 0; ; pageNo++ {
+		page, err := d.BulkTransferTransactions(batchReference, pageNo, exportPageSize)
+		if err != nil {
+			return err
+		}
+		transfers = append(transfers, page.ResponseBody.Content...)
+		if page.ResponseBody.Last || len(page.ResponseBody.Content) == 0 || pageNo+1 >= page.ResponseBody.TotalPages {
+			break
+		}
+	}
+	return WriteTransfersCSV(w, transfers)
+}
+
+// csvText neutralises a text cell that a spreadsheet would run as a formula.
+func csvText(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func normalizeHeader(h string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+}
+
+func isBlankRecord(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -1,19 +1,58 @@
 package gomonnify
 
 import (
+	"context"
+	"github.com/jcobhams/gomonnify/params"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 )
 
 type (
 	Environment string
 
+	// Amount is an exact monetary value in minor units (kobo). See params.Amount
+	Amount = params.Amount
+
+	// Metadata holds arbitrary key/value pairs attached to a transaction. See params.Metadata
+	Metadata = params.Metadata
+
+	MatchStatus string
+
+	// TransferStatus is the status of a single transfer, or of a transfer in a batch.
+	TransferStatus string
+
+	// BatchStatus is the status of a bulk transfer batch.
+	BatchStatus string
+
+	WalletTransactionType string
+
 	requestAuthType string
 
+	// call describes a request to a Monnify endpoint. path is relative to the api base url, see urlPath.
+	// ctx is optional and bounds the request when set.
+	call struct {
+		ctx    context.Context
+		method string
+		path   string
+		query  url.Values
+		auth   requestAuthType
+		body   interface{}
+	}
+
+	// responseMeta is implemented by every response through the embedded apiResponseMeta.
+	responseMeta interface {
+		meta() *apiResponseMeta
+	}
+
 	base struct {
 		HTTPClient *http.Client
 		APIBaseUrl string
 		Config     *Config
+
+		banksMu sync.RWMutex
+		banks   *BanksResponse
 	}
 
 	disbursements struct {
@@ -28,31 +67,61 @@ type (
 		*base
 	}
 
+	verification struct {
+		*base
+	}
+
+	wallets struct {
+		*base
+	}
+
 	general struct {
 		*base
-		banks *BanksResponse
 	}
 
 	Monnify struct {
-		General *general
+		General GeneralAPI
 		//Invoicing        *invoicing
-		Disbursements    *disbursements
-		ReservedAccounts *reservedAccounts
+		Disbursements    DisbursementsAPI
+		ReservedAccounts ReservedAccountsAPI
+		Verification     VerificationAPI
+		Wallets          WalletsAPI
 	}
 
 	// Config is used to initialize the Monnify client.
-	// Environment - sets the current environment. Sandbox or Live
+	// Environment - sets the current environment. Sandbox, Live or Test
 	// APIKey - well pretty obvious :)
 	// SecretKey - same as above
 	// RequestTimeout - used to set a deadline on the HTTP requests made. defaults to 5seconds.
 	// setting it to 0 to ignores timeout and could make request wait indefinitely (not recommended).
 	// DefaultContractCode - used by some endpoints. is not provided in the endpoint method params. Not required.
+	// Transport - optional http.RoundTripper used to make the HTTP requests, e.g. testhelpers.Recorder to record and
+	// replay sandbox traffic. defaults to http.DefaultTransport.
+	// BaseURL - overrides the API base url picked from the Environment, e.g. to point the client at a mock server.
+	// Required in the Test environment.
+	// TestModeFromEnv - opts in to the GOMONNIFY_TESTMODE and GOMONNIFY_TESTURL env vars. When set and
+	// GOMONNIFY_TESTMODE is ON, the Test environment is used with GOMONNIFY_TESTURL as BaseURL. Ignored otherwise.
+	// AllowUnsuccessfulResponses - returns HTTP 200 responses as they are, even when Monnify flags them with
+	// requestSuccessful false, leaving the caller to check RequestSuccessful and ResponseCode. Off by default.
 	Config struct {
 		Environment         Environment
 		APIKey              string
 		SecretKey           string
 		RequestTimeout      time.Duration
 		DefaultContractCode string
+		Transport           http.RoundTripper
+		BaseURL             string
+		TestModeFromEnv     bool
+
+		AllowUnsuccessfulResponses bool
+	}
+
+	// APIError is returned when Monnify does not accept a request, either with an HTTP error status or with a 200
+	// response that has requestSuccessful false or a non zero responseCode.
+	APIError struct {
+		HTTPStatus      int
+		ResponseCode    string
+		ResponseMessage string
 	}
 
 	// Endpoint Responses || Method Return Values
@@ -62,23 +131,35 @@ type (
 		ResponseCode      string `json:"responseCode"`
 	}
 
+	InitializeTransactionResponse struct {
+		apiResponseMeta
+		ResponseBody struct {
+			TransactionReference string   `json:"transactionReference"`
+			PaymentReference     string   `json:"paymentReference"`
+			MerchantName         string   `json:"merchantName"`
+			APIKey               string   `json:"apiKey"`
+			EnabledPaymentMethod []string `json:"enabledPaymentMethod"`
+			CheckoutURL          string   `json:"checkoutUrl"`
+		} `json:"responseBody"`
+	}
+
 	GeneralTransactionResponse struct {
 		apiResponseMeta
 		ResponseBody GeneralTransaction `json:"responseBody"`
 	}
 
 	GeneralTransaction struct {
-		TransactionReference string `json:"transactionReference"`
-		PaymentReference     string `json:"paymentReference"`
-		AmountPaid           string `json:"amountPaid"`
-		TotalPayable         string `json:"totalPayable"`
-		SettlementAmount     string `json:"settlementAmount"`
-		PaidOn               string `json:"paidOn"`
-		PaymentStatus        string `json:"paymentStatus"`
-		PaymentDescription   string `json:"paymentDescription"`
-		TransactionHash      string `json:"transactionHash"`
-		Currency             string `json:"currency"`
-		PaymentMethod        string `json:"paymentMethod"`
+		TransactionReference string    `json:"transactionReference"`
+		PaymentReference     string    `json:"paymentReference"`
+		AmountPaid           Amount    `json:"amountPaid"`
+		TotalPayable         Amount    `json:"totalPayable"`
+		SettlementAmount     Amount    `json:"settlementAmount"`
+		PaidOn               Timestamp `json:"paidOn"`
+		PaymentStatus        string    `json:"paymentStatus"`
+		PaymentDescription   string    `json:"paymentDescription"`
+		TransactionHash      string    `json:"transactionHash"`
+		Currency             string    `json:"currency"`
+		PaymentMethod        string    `json:"paymentMethod"`
 		Product              struct {
 			Type      string `json:"type"`
 			Reference string `json:"reference"`
@@ -98,17 +179,16 @@ type (
 			Email string `json:"email"`
 			Name  string `json:"name"`
 		} `json:"customer"`
-		MetaData struct {
-			Name string `json:"name"`
-			Age  string `json:"age"`
-		}
+		MetaData Metadata `json:"metaData"`
+
+		amountPaidRaw string
 	}
 
 	accountDetails struct {
 		AccountName   string `json:"accountName"`
 		AccountNumber string `json:"accountNumber"`
 		BankCode      string `json:"bankCode"`
-		AmountPaid    string `json:"amountPaid"`
+		AmountPaid    Amount `json:"amountPaid"`
 	}
 
 	LoginResponse struct {
@@ -122,20 +202,20 @@ type (
 	ReserveAccountResponse struct {
 		apiResponseMeta
 		ResponseBody struct {
-			ContractCode         string `json:"contractCode"`
-			AccountReference     string `json:"accountReference"`
-			AccountName          string `json:"accountName"`
-			CurrencyCode         string `json:"currencyCode"`
-			CustomerEmail        string `json:"customerEmail"`
-			CustomerName         string `json:"customerName"`
-			AccountNumber        string `json:"accountNumber"`
-			BankName             string `json:"bankName"`
-			BankCode             string `json:"bankCode"`
-			CollectionChannel    string `json:"collectionChannel"`
-			ReservationReference string `json:"reservationReference"`
-			ReservedAccountType  string `json:"reservedAccountType"`
-			Status               string `json:"status"`
-			CreatedOn            string `json:"createdOn"`
+			ContractCode         string    `json:"contractCode"`
+			AccountReference     string    `json:"accountReference"`
+			AccountName          string    `json:"accountName"`
+			CurrencyCode         string    `json:"currencyCode"`
+			CustomerEmail        string    `json:"customerEmail"`
+			CustomerName         string    `json:"customerName"`
+			AccountNumber        string    `json:"accountNumber"`
+			BankName             string    `json:"bankName"`
+			BankCode             string    `json:"bankCode"`
+			CollectionChannel    string    `json:"collectionChannel"`
+			ReservationReference string    `json:"reservationReference"`
+			ReservedAccountType  string    `json:"reservedAccountType"`
+			Status               string    `json:"status"`
+			CreatedOn            Timestamp `json:"createdOn"`
 			IncomeSplitConfig    []struct {
 				SubAccountCode  string  `json:"subAccountCode"`
 				FeePercentage   float64 `json:"feePercentage"`
@@ -191,45 +271,45 @@ type (
 			Name         string `json:"name"`
 			MerchantCode string `json:"merchantCode"`
 		} `json:"customerDTO"`
-		ProviderAmount       float64 `json:"providerAmount"`
-		PaymentMethod        string  `json:"paymentMethod"`
-		CreatedOn            string  `json:"createdOn"`
-		Amount               float64 `json:"amount"`
-		Flagged              bool    `json:"flagged"`
-		ProviderCode         string  `json:"providerCode"`
-		Fee                  float64 `json:"fee"`
-		CurrencyCode         string  `json:"currencyCode"`
-		CompletedOn          string  `json:"completedOn"`
-		PaymentDescription   string  `json:"paymentDescription"`
-		PaymentStatus        string  `json:"paymentStatus"`
-		TransactionReference string  `json:"transactionReference"`
-		PaymentReference     string  `json:"paymentReference"`
-		MerchantCode         string  `json:"merchantCode"`
-		MerchantName         string  `json:"merchantName"`
-		PayableAmount        float64 `json:"payableAmount"`
-		AmountPaid           float64 `json:"amountPaid"`
-		Completed            bool    `json:"completed"`
+		ProviderAmount       Amount    `json:"providerAmount"`
+		PaymentMethod        string    `json:"paymentMethod"`
+		CreatedOn            Timestamp `json:"createdOn"`
+		Amount               Amount    `json:"amount"`
+		Flagged              bool      `json:"flagged"`
+		ProviderCode         string    `json:"providerCode"`
+		Fee                  Amount    `json:"fee"`
+		CurrencyCode         string    `json:"currencyCode"`
+		CompletedOn          Timestamp `json:"completedOn"`
+		PaymentDescription   string    `json:"paymentDescription"`
+		PaymentStatus        string    `json:"paymentStatus"`
+		TransactionReference string    `json:"transactionReference"`
+		PaymentReference     string    `json:"paymentReference"`
+		MerchantCode         string    `json:"merchantCode"`
+		MerchantName         string    `json:"merchantName"`
+		PayableAmount        Amount    `json:"payableAmount"`
+		AmountPaid           Amount    `json:"amountPaid"`
+		Completed            bool      `json:"completed"`
 	}
 
 	SingleTransferResponse struct {
 		apiResponseMeta
 		ResponseBody struct {
-			Amount      float64 `json:"amount"`
-			Reference   string  `json:"reference"`
-			Status      string  `json:"status"`
-			DateCreated string  `json:"dateCreated"`
+			Amount      Amount         `json:"amount"`
+			Reference   string         `json:"reference"`
+			Status      TransferStatus `json:"status"`
+			DateCreated Timestamp      `json:"dateCreated"`
 		}
 	}
 
 	BulkTransferResponse struct {
 		apiResponseMeta
 		ResponseBody struct {
-			TotalAmount       float64 `json:"totalAmount"`
-			TotalFee          float64 `json:"totalFee"`
-			BatchReference    string  `json:"batchReference"`
-			BatchStatus       string  `json:"batchStatus"`
-			TotalTransactions int     `json:"totalTransactions"`
-			DateCreated       string  `json:"date_created"`
+			TotalAmount       Amount      `json:"totalAmount"`
+			TotalFee          Amount      `json:"totalFee"`
+			BatchReference    string      `json:"batchReference"`
+			BatchStatus       BatchStatus `json:"batchStatus"`
+			TotalTransactions int         `json:"totalTransactions"`
+			DateCreated       Timestamp   `json:"date_created"`
 		}
 	}
 
@@ -239,33 +319,35 @@ type (
 	}
 
 	SingleTransferDetails struct {
-		Amount        float64 `json:"amount"`
-		Reference     string  `json:"reference"`
-		Narration     string  `json:"narration"`
-		BankCode      string  `json:"bankCode"`
-		AccountNumber string  `json:"accountNumber"`
-		Currency      string  `json:"currency"`
-		AccountName   string  `json:"accountName"`
-		BankName      string  `json:"bankName"`
-		DateCreated   string  `json:"dateCreated"`
-		Fee           float64 `json:"fee"`
-		Status        string  `json:"status"`
+		Amount        Amount         `json:"amount"`
+		Reference     string         `json:"reference"`
+		Narration     string         `json:"narration"`
+		BankCode      string         `json:"bankCode"`
+		AccountNumber string         `json:"accountNumber"`
+		Currency      string         `json:"currency"`
+		AccountName   string         `json:"accountName"`
+		BankName      string         `json:"bankName"`
+		DateCreated   Timestamp      `json:"dateCreated"`
+		Fee           Amount         `json:"fee"`
+		Status        TransferStatus `json:"status"`
 	}
 
 	BulkTransferDetailsResponse struct {
 		apiResponseMeta
-		ResponseBody struct {
-			Title             string  `json:"title"`
-			TotalAmount       float64 `json:"totalAmount"`
-			TotalFee          float64 `json:"totalFee"`
-			BatchReference    string  `json:"batchReference"`
-			TotalTransactions int     `json:"totalTransactions"`
-			FailedCount       int     `json:"failedCount"`
-			SuccessfulCount   int     `json:"successfulCount"`
-			PendingCount      int     `json:"pendingCount"`
-			BatchStatus       string  `json:"batchStatus"`
-			DateCreated       string  `json:"dateCreated"`
-		} `json:"responseBody"`
+		ResponseBody BulkTransferDetails `json:"responseBody"`
+	}
+
+	BulkTransferDetails struct {
+		Title             string      `json:"title"`
+		TotalAmount       Amount      `json:"totalAmount"`
+		TotalFee          Amount      `json:"totalFee"`
+		BatchReference    string      `json:"batchReference"`
+		TotalTransactions int         `json:"totalTransactions"`
+		FailedCount       int         `json:"failedCount"`
+		SuccessfulCount   int         `json:"successfulCount"`
+		PendingCount      int         `json:"pendingCount"`
+		BatchStatus       BatchStatus `json:"batchStatus"`
+		DateCreated       Timestamp   `json:"dateCreated"`
 	}
 
 	TransferTransactionsResponse struct {
@@ -312,8 +394,8 @@ type (
 	WalletBalanceResponse struct {
 		apiResponseMeta
 		ResponseBody struct {
-			AvailableBalance float64 `json:"availableBalance"`
-			LedgerBalance    float64 `json:"ledgerBalance"`
+			AvailableBalance Amount `json:"availableBalance"`
+			LedgerBalance    Amount `json:"ledgerBalance"`
 		} `json:"responseBody"`
 	}
 
@@ -336,4 +418,132 @@ type (
 		BaseUSSDCode         string `json:"baseUssdCode"`
 		TransferUSSDTemplate string `json:"transferUssdTemplate"`
 	}
+
+	BVNAccountMatchResponse struct {
+		apiResponseMeta
+		ResponseBody struct {
+			BVN             string      `json:"bvn"`
+			AccountNumber   string      `json:"accountNumber"`
+			AccountName     string      `json:"accountName"`
+			MatchStatus     MatchStatus `json:"matchStatus"`
+			MatchPercentage float64     `json:"matchPercentage"`
+		} `json:"responseBody"`
+	}
+
+	BVNDetailsResponse struct {
+		apiResponseMeta
+		ResponseBody struct {
+			BVN  string `json:"bvn"`
+			Name struct {
+				MatchStatus     MatchStatus `json:"matchStatus"`
+				MatchPercentage float64     `json:"matchPercentage"`
+			} `json:"name"`
+			DateOfBirth MatchStatus `json:"dateOfBirth"`
+			MobileNo    MatchStatus `json:"mobileNo"`
+		} `json:"responseBody"`
+	}
+
+	WalletResponse struct {
+		apiResponseMeta
+		ResponseBody Wallet `json:"responseBody"`
+	}
+
+	WalletsResponse struct {
+		apiResponseMeta
+		ResponseBody struct {
+			Content  []Wallet `json:"content"`
+			Pageable struct {
+				Sort struct {
+					Sorted   bool `json:"sorted"`
+					Unsorted bool `json:"unsorted"`
+					Empty    bool `json:"empty"`
+				} `json:"sort"`
+				PageSize   int  `json:"pageSize"`
+				PageNumber int  `json:"pageNumber"`
+				Offset     int  `json:"offset"`
+				Unpaged    bool `json:"unpaged"`
+				Paged      bool `json:"paged"`
+			} `json:"pageable"`
+			TotalElements int  `json:"totalElements"`
+			TotalPages    int  `json:"totalPages"`
+			Last          bool `json:"last"`
+			Sort          struct {
+				Sorted   bool `json:"sorted"`
+				Unsorted bool `json:"unsorted"`
+				Empty    bool `json:"empty"`
+			} `json:"sort"`
+			First            bool `json:"first"`
+			NumberOfElements int  `json:"numberOfElements"`
+			Size             int  `json:"size"`
+			Number           int  `json:"number"`
+			Empty            bool `json:"empty"`
+		} `json:"responseBody"`
+	}
+
+	Wallet struct {
+		WalletName      string `json:"walletName"`
+		WalletReference string `json:"walletReference"`
+		CustomerName    string `json:"customerName"`
+		CustomerEmail   string `json:"customerEmail"`
+		FeeBearer       string `json:"feeBearer"`
+		BVNDetails      struct {
+			BVN         string `json:"bvn"`
+			DateOfBirth string `json:"bvnDateOfBirth"`
+		} `json:"bvnDetails"`
+		AccountNumber       string `json:"accountNumber"`
+		AccountName         string `json:"accountName"`
+		TopUpAccountDetails struct {
+			AccountNumber string `json:"accountNumber"`
+			AccountName   string `json:"accountName"`
+			BankCode      string `json:"bankCode"`
+			BankName      string `json:"bankName"`
+		} `json:"topUpAccountDetails"`
+	}
+
+	WalletStatementResponse struct {
+		apiResponseMeta
+		ResponseBody struct {
+			Content  []WalletStatementEntry `json:"content"`
+			Pageable struct {
+				Sort struct {
+					Sorted   bool `json:"sorted"`
+					Unsorted bool `json:"unsorted"`
+					Empty    bool `json:"empty"`
+				} `json:"sort"`
+				PageSize   int  `json:"pageSize"`
+				PageNumber int  `json:"pageNumber"`
+				Offset     int  `json:"offset"`
+				Unpaged    bool `json:"unpaged"`
+				Paged      bool `json:"paged"`
+			} `json:"pageable"`
+			TotalElements int  `json:"totalElements"`
+			TotalPages    int  `json:"totalPages"`
+			Last          bool `json:"last"`
+			Sort          struct {
+				Sorted   bool `json:"sorted"`
+				Unsorted bool `json:"unsorted"`
+				Empty    bool `json:"empty"`
+			} `json:"sort"`
+			First            bool `json:"first"`
+			NumberOfElements int  `json:"numberOfElements"`
+			Size             int  `json:"size"`
+			Number           int  `json:"number"`
+			Empty            bool `json:"empty"`
+		} `json:"responseBody"`
+	}
+
+	// WalletStatementEntry is a single ledger entry on a wallet.
+	// BalanceBefore and BalanceAfter hold the running available balance around the entry.
+	WalletStatementEntry struct {
+		WalletTransactionReference  string                `json:"walletTransactionReference"`
+		MonnifyTransactionReference string                `json:"monnifyTransactionReference"`
+		TransactionType             WalletTransactionType `json:"transactionType"`
+		Amount                      Amount                `json:"amount"`
+		Fee                         Amount                `json:"fee"`
+		BalanceBefore               Amount                `json:"availableBalanceBefore"`
+		BalanceAfter                Amount                `json:"availableBalanceAfter"`
+		Narration                   string                `json:"narration"`
+		Status                      string                `json:"status"`
+		TransactionDate             Timestamp             `json:"transactionDate"`
+	}
 )
@@ -0,0 +1,75 @@
+package gomonnify
+
+import (
+	"fmt"
+	"github.com/jcobhams/gomonnify/params"
+)
+
+type (
+	// ValidationError describes why a single params field is invalid. See params.ValidationError
+	ValidationError = params.ValidationError
+
+	// ValidationErrors is returned, before any request is sent, with every invalid field of the params.
+	ValidationErrors = params.ValidationErrors
+
+	validatable interface {
+		Validate() error
+	}
+
+	bankCodeField struct {
+		field string
+		code  string
+	}
+)
+
+// validate checks params before they are sent. On top of the checks done by the params themselves, bank codes are
+// checked against the bank list when it has already been fetched through GetBanksUseCache. The list is never fetched
+// just to validate params.
+func (b *base) validate(p validatable) error {
+	var errs ValidationErrors
+	if err := p.Validate(); err != nil {
+		ve, ok := err.(ValidationErrors)
+		if !ok {
+			return err
+		}
+		errs = ve
+	}
+
+	if banks := b.cachedBanks(); banks != nil {
+		known := map[string]bool{}
+		for _, bank := range banks.ResponseBody {
+			known[bank.Code] = true
+		}
+		for _, f := range bankCodeFields(p) {
+			if _, invalid := errs.Field(f.field); invalid || f.code == "" || known[f.code] {
+				continue
+			}
+			errs = append(errs, ValidationError{Field: f.field, Message: fmt.Sprintf("%v is not a known bank code", f.code)})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// bankCodeFields lists the bank codes held by p along with the field they are in.
+func bankCodeFields(p validatable) []bankCodeField {
+	var fields []bankCodeField
+	switch v := p.(type) {
+	case params.SingleTransferParam:
+		fields = append(fields, bankCodeField{"bankCode", v.BankCode})
+	case params.BulkTransferParam:
+		for i, t := range v.TransactionList {
+			fields = append(fields, bankCodeField{fmt.Sprintf("transactionList[%d].bankCode", i), t.BankCode})
+		}
+	case params.BVNAccountMatchParam:
+		fields = append(fields, bankCodeField{"bankCode", v.BankCode})
+	case params.ReserveAccountParam:
+		for i, a := range v.AllowedPaymentSources.BankAccounts {
+			fields = append(fields, bankCodeField{fmt.Sprintf("allowedPaymentSources.bankAccounts[%d].bankCode", i), a.BankCode})
+		}
+	}
+	return fields
+}
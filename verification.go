@@ -0,0 +1,37 @@
+package gomonnify
+
+import (
+	"github.com/jcobhams/gomonnify/params"
+	"net/http"
+)
+
+// MatchBVNAndAccount checks that the provided BVN belongs to the holder of the bank account.
+// The response carries the match status and how closely the names on both records match.
+// Docs: https://docs.teamapt.com/display/MON/BVN+and+Account+Name+Match
+func (v *verification) MatchBVNAndAccount(params params.BVNAccountMatchParam) (*BVNAccountMatchResponse, error) {
+	if err := v.validate(params); err != nil {
+		return nil, err
+	}
+
+	return do[BVNAccountMatchResponse](v.base, call{
+		method: http.MethodPost,
+		path:   "/v1/vas/bvn-account-match",
+		auth:   requestAuthTypeBearer,
+		body:   params,
+	})
+}
+
+// VerifyBVN compares the provided name, date of birth and mobile number with the details registered against the BVN.
+// Docs: https://docs.teamapt.com/display/MON/BVN+Information+Verification
+func (v *verification) VerifyBVN(params params.BVNDetailsParam) (*BVNDetailsResponse, error) {
+	if err := v.validate(params); err != nil {
+		return nil, err
+	}
+
+	return do[BVNDetailsResponse](v.base, call{
+		method: http.MethodPost,
+		path:   "/v1/vas/bvn-details-match",
+		auth:   requestAuthTypeBearer,
+		body:   params,
+	})
+}
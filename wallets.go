@@ -0,0 +1,56 @@
+package gomonnify
+
+import (
+	"errors"
+	"github.com/jcobhams/gomonnify/params"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Create provisions a new disbursement wallet for the customer. The walletReference must be unique per wallet
+// and can later be used to fund or debit the wallet.
+// Docs: https://docs.teamapt.com/display/MON/Create+Wallet
+func (w *wallets) Create(walletReference, walletName string, customer params.WalletCustomerParam) (*WalletResponse, error) {
+	if walletReference == "" {
+		return nil, errors.New("walletReference is required")
+	}
+	if err := w.validate(customer); err != nil {
+		return nil, err
+	}
+
+	param := struct {
+		WalletReference string                `json:"walletReference"`
+		WalletName      string                `json:"walletName"`
+		CustomerName    string                `json:"customerName"`
+		CustomerEmail   string                `json:"customerEmail"`
+		BVNDetails      params.WalletBVNParam `json:"bvnDetails"`
+	}{
+		WalletReference: walletReference,
+		WalletName:      walletName,
+		CustomerName:    customer.Name,
+		CustomerEmail:   customer.Email,
+		BVNDetails:      customer.BVNDetails,
+	}
+	return do[WalletResponse](w.base, call{
+		method: http.MethodPost,
+		path:   "/v1/disbursements/wallet",
+		auth:   requestAuthTypeBasic,
+		body:   param,
+	})
+}
+
+// List returns the wallets belonging to the customer with the provided email.
+// Docs: https://docs.teamapt.com/display/MON/Get+Wallets
+func (w *wallets) List(customerEmail string, pageNo, pageSize int) (*WalletsResponse, error) {
+	return do[WalletsResponse](w.base, call{
+		method: http.MethodGet,
+		path:   "/v1/disbursements/wallet",
+		query: url.Values{
+			"customerEmail": {customerEmail},
+			"pageNo":        {strconv.Itoa(pageNo)},
+			"pageSize":      {strconv.Itoa(pageSize)},
+		},
+		auth: requestAuthTypeBasic,
+	})
+}